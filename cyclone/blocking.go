@@ -0,0 +1,104 @@
+package cyclone
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mediocregopher/radix/v3"
+	"github.com/mediocregopher/radix/v3/resp/resp2"
+)
+
+// Direction is a side of a list used by LMOVE and BLMOVE.
+type Direction string
+
+const (
+	// Left is the head of a list.
+	Left Direction = "LEFT"
+	// Right is the tail of a list.
+	Right Direction = "RIGHT"
+)
+
+// connPool keeps dedicated connections for blocking commands,
+// so that they never hold connections of the shared pool.
+type connPool struct {
+	dial func() (radix.Conn, error)
+
+	mu     sync.Mutex
+	idle   []radix.Conn
+	size   int
+	closed bool
+}
+
+func newConnPool(size int, dial func() (radix.Conn, error)) *connPool {
+	return &connPool{dial: dial, size: size}
+}
+
+// get returns idle connection or dials a new one.
+func (p *connPool) get() (radix.Conn, error) {
+	p.mu.Lock()
+	if n := len(p.idle); n > 0 {
+		conn := p.idle[n-1]
+		p.idle = p.idle[:n-1]
+		p.mu.Unlock()
+		return conn, nil
+	}
+	p.mu.Unlock()
+	return p.dial()
+}
+
+// put returns conn to the pool when it is reusable, closes it otherwise.
+func (p *connPool) put(conn radix.Conn, reusable bool) {
+	p.mu.Lock()
+	if reusable && !p.closed && len(p.idle) < p.size {
+		p.idle = append(p.idle, conn)
+		conn = nil
+	}
+	p.mu.Unlock()
+
+	if conn != nil {
+		conn.Close()
+	}
+}
+
+func (p *connPool) close() {
+	p.mu.Lock()
+	idle := p.idle
+	p.idle = nil
+	p.closed = true
+	p.mu.Unlock()
+
+	for _, conn := range idle {
+		conn.Close()
+	}
+}
+
+// doBlocking performs blocking action on a dedicated connection when Cyclone
+// was created by Connect. Otherwise action is performed by Raw client, in such
+// case read timeout of its connections must exceed the blocking timeout.
+func (c *Cyclone) doBlocking(a radix.Action) error {
+	if c.blocking == nil {
+		return c.do(a)
+	}
+
+	conn, err := c.blocking.get()
+	if err != nil {
+		return err
+	}
+
+	err = c.bind(borrowedConn{conn}).Do(a)
+	var respErr resp2.Error
+	c.blocking.put(conn, err == nil || errors.As(err, &respErr))
+	return wrapErr(err)
+}
+
+// formatTimeout formats timeout in seconds as expected by blocking commands.
+// Positive timeouts shorter than a millisecond are rounded up, as redis
+// truncates them to 0 which blocks forever.
+func formatTimeout(timeout time.Duration) string {
+	if timeout > 0 && timeout < time.Millisecond {
+		timeout = time.Millisecond
+	}
+	return strconv.FormatFloat(timeout.Seconds(), 'f', -1, 64)
+}
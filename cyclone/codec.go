@@ -0,0 +1,250 @@
+package cyclone
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"reflect"
+
+	"github.com/mediocregopher/radix/v3"
+)
+
+// Transformer transforms encoded values before they are written and restores
+// them after they are read, e.g. compression or encryption. Decode should return
+// values which were not transformed (e.g. written before transformer was set) as is,
+// unless such values must be rejected (see Encryption.AllowPlaintext).
+type Transformer interface {
+	Encode(data []byte) ([]byte, error)
+	Decode(data []byte) ([]byte, error)
+}
+
+// Chain returns transformer which encodes values with transformers in order
+// and decodes them in reverse order, e.g. compression before encryption:
+//
+//   redis.WithTransformer(cyclone.Chain(compression, encryption))
+//
+func Chain(transformers ...Transformer) Transformer {
+	return chain(transformers)
+}
+
+type chain []Transformer
+
+func (c chain) Encode(data []byte) (_ []byte, err error) {
+	for _, t := range c {
+		if data, err = t.Encode(data); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+func (c chain) Decode(data []byte) (_ []byte, err error) {
+	for i := len(c) - 1; i >= 0; i-- {
+		if data, err = c[i].Decode(data); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// Codec encodes Go values into redis values and decodes them back.
+// Codecs for other formats (e.g. MessagePack or protobuf) can be plugged in
+// by implementing this interface.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+var (
+	// JSON encodes values with encoding/json.
+	JSON Codec = jsonCodec{}
+
+	// Gob encodes values with encoding/gob.
+	Gob Codec = gobCodec{}
+)
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+type gobCodec struct{}
+
+func (gobCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(v)
+	return buf.Bytes(), err
+}
+
+func (gobCodec) Unmarshal(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// encode encodes every value of vals starting at index from with step using
+// Cyclone's codec and transformer. Values are returned as is when neither is set.
+// With codec only maps passed in place of field/value pairs (step 2) are flattened,
+// other slices and maps are encoded as a single value.
+func (c *Cyclone) encode(vals []interface{}, from, step int) ([]interface{}, error) {
+	if c.codec == nil && c.transformer == nil {
+		return vals, nil
+	}
+	if c.codec == nil {
+		vals = flattenArgs(vals)
+	} else if step == 2 {
+		vals = flattenPairs(vals)
+	}
+	encoded := make([]interface{}, len(vals))
+	copy(encoded, vals)
+	for i := from; i < len(vals); i += step {
+		b, err := c.marshal(vals[i])
+		if err != nil {
+			return nil, err
+		}
+		if b, err = c.transform(b); err != nil {
+			return nil, err
+		}
+		encoded[i] = b
+	}
+	return encoded, nil
+}
+
+// flattenArgs flattens slices and maps in vals the same way radix.FlatCmd does.
+func flattenArgs(vals []interface{}) []interface{} {
+	flat := make([]interface{}, 0, len(vals))
+	for _, v := range vals {
+		rv := reflect.ValueOf(v)
+		switch rv.Kind() {
+		case reflect.Slice, reflect.Array:
+			if rv.Type().Elem().Kind() == reflect.Uint8 {
+				flat = append(flat, v)
+				continue
+			}
+			for i := 0; i < rv.Len(); i++ {
+				flat = append(flat, rv.Index(i).Interface())
+			}
+		case reflect.Map:
+			iter := rv.MapRange()
+			for iter.Next() {
+				flat = append(flat, iter.Key().Interface(), iter.Value().Interface())
+			}
+		default:
+			flat = append(flat, v)
+		}
+	}
+	return flat
+}
+
+// flattenPairs flattens maps of field/value pairs in vals, e.g. in
+// Hash.Set(map[string]interface{}{...}). Values of pairs are kept as is.
+func flattenPairs(vals []interface{}) []interface{} {
+	flat := make([]interface{}, 0, len(vals))
+	for _, v := range vals {
+		if rv := reflect.ValueOf(v); len(flat)%2 == 0 && rv.Kind() == reflect.Map {
+			iter := rv.MapRange()
+			for iter.Next() {
+				flat = append(flat, iter.Key().Interface(), iter.Value().Interface())
+			}
+			continue
+		}
+		flat = append(flat, v)
+	}
+	return flat
+}
+
+// marshal encodes v with Cyclone's codec. Without codec v is formatted
+// the same way as hash fields in SetStruct.
+func (c *Cyclone) marshal(v interface{}) ([]byte, error) {
+	if c.codec != nil {
+		return c.codec.Marshal(v)
+	}
+	if v == nil {
+		return []byte{}, nil
+	}
+	s, err := formatValue(reflect.ValueOf(v), JSON)
+	return []byte(s), err
+}
+
+// encodeValue encodes single value with Cyclone's codec and transformer
+// the same way encode does.
+func (c *Cyclone) encodeValue(v interface{}) (string, error) {
+	b, err := c.marshal(v)
+	if err != nil {
+		return "", err
+	}
+	b, err = c.transform(b)
+	return string(b), err
+}
+
+// transform transforms encoded value with Cyclone's transformer.
+func (c *Cyclone) transform(data []byte) ([]byte, error) {
+	if c.transformer == nil {
+		return data, nil
+	}
+	return c.transformer.Encode(data)
+}
+
+// restore reverses Cyclone's transformer on value read from redis.
+func (c *Cyclone) restore(s string) (string, error) {
+	if c.transformer == nil {
+		return s, nil
+	}
+	data, err := c.transformer.Decode([]byte(s))
+	return string(data), err
+}
+
+// restoreAll restores every value of vals in place.
+func (c *Cyclone) restoreAll(vals []string) (err error) {
+	if c.transformer == nil {
+		return nil
+	}
+	for i := range vals {
+		if vals[i], err = c.restore(vals[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// doInto performs cmd and decodes its bulk string reply into v with Cyclone's codec
+// and transformer or radix when neither is set. ErrNil is returned for nil reply.
+func (c *Cyclone) doInto(v interface{}, cmd string, args ...string) error {
+	if c.codec == nil && c.transformer == nil {
+		mn := radix.MaybeNil{Rcv: v}
+		return nilErr(&mn, c.do(radix.Cmd(&mn, cmd, args...)))
+	}
+
+	var data []byte
+	mn := radix.MaybeNil{Rcv: &data}
+	if err := nilErr(&mn, c.do(radix.Cmd(&mn, cmd, args...))); err != nil {
+		return err
+	}
+	if c.transformer != nil {
+		var err error
+		if data, err = c.transformer.Decode(data); err != nil {
+			return err
+		}
+	}
+	if c.codec != nil {
+		return c.codec.Unmarshal(data, v)
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("cyclone: non-nil pointer expected")
+	}
+	return parseValue(rv.Elem(), string(data), JSON)
+}
+
+// nestedCodec returns codec for nested values, JSON when no codec is set.
+func (c *Cyclone) nestedCodec() Codec {
+	if c.codec == nil {
+		return JSON
+	}
+	return c.codec
+}
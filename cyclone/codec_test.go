@@ -0,0 +1,141 @@
+package cyclone
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"strings"
+	"testing"
+
+	"github.com/franela/goblin"
+)
+
+type codecJob struct {
+	ID   int
+	Args []string
+}
+
+func TestCodec(t *testing.T) {
+	g := goblin.Goblin(t)
+
+	g.Describe("Codecs", func() {
+		g.It("Round-trip values", func() {
+			for _, codec := range []Codec{JSON, Gob} {
+				data, err := codec.Marshal(codecJob{ID: 1, Args: []string{"a"}})
+				g.Assert(err).Eql(nil)
+
+				var job codecJob
+				g.Assert(codec.Unmarshal(data, &job)).Eql(nil)
+				g.Assert(job).Eql(codecJob{ID: 1, Args: []string{"a"}})
+			}
+		})
+
+		g.It("Chains transformers", func() {
+			gz := NewCompression(Gzip, CompressOptions{Threshold: 1})
+			key, _ := NewAESGCM(make([]byte, 32))
+			enc, _ := NewEncryption("k", map[string]cipher.AEAD{"k": key})
+			chained := Chain(gz, enc)
+
+			value := []byte(strings.Repeat("a", 100))
+			encoded, err := chained.Encode(value)
+			g.Assert(err).Eql(nil)
+			g.Assert(bytes.HasPrefix(encoded, encryptMagic)).IsTrue()
+			g.Assert(len(encoded) < len(value)).IsTrue()
+
+			decoded, err := chained.Decode(encoded)
+			g.Assert(err).Eql(nil)
+			g.Assert(decoded).Eql(value)
+		})
+	})
+
+	withConn(func(c *Cyclone) {
+		g.Describe(".WithCodec", func() {
+			g.It("Is nil by default", func() {
+				g.Assert(c.Codec() == nil).IsTrue()
+				g.Assert(c.WithCodec(Gob).Codec()).Eql(Gob)
+				g.Assert(c.Codec() == nil).IsTrue()
+			})
+
+			g.It("Encodes list elements", func() {
+				jobs := c.List("CodecList").WithCodec(JSON)
+				jobs.RPush(codecJob{ID: 1}, codecJob{ID: 2, Args: []string{"x"}})
+
+				raw, _ := c.List("CodecList").Range(0, -1)
+				g.Assert(raw).Eql([]string{`{"ID":1,"Args":null}`, `{"ID":2,"Args":["x"]}`})
+
+				var job codecJob
+				g.Assert(jobs.PopInto(&job)).Eql(nil)
+				g.Assert(job).Eql(codecJob{ID: 1})
+				g.Assert(jobs.RPopInto(&job)).Eql(nil)
+				g.Assert(job).Eql(codecJob{ID: 2, Args: []string{"x"}})
+				g.Assert(jobs.PopInto(&job)).Eql(ErrNil)
+			})
+
+			g.It("Encodes hash values", func() {
+				h := c.WithCodec(Gob).Hash("CodecHash")
+				h.Set("job", codecJob{ID: 3}, "n", 5)
+
+				var job codecJob
+				g.Assert(h.GetInto("job", &job)).Eql(nil)
+				g.Assert(job).Eql(codecJob{ID: 3})
+
+				var n int
+				g.Assert(h.GetInto("n", &n)).Eql(nil)
+				g.Assert(n).Eql(5)
+				g.Assert(h.GetInto("missing", &n)).Eql(ErrNil)
+			})
+
+			g.It("Encodes values set in place", func() {
+				h := c.WithCodec(JSON).Hash("CodecHashSetNX")
+				h.SetNX("a", "x")
+				c.WithCodec(JSON).Pipeline(func(p *Pipe) {
+					p.Hash("CodecHashSetNX").SetNX("b", "y")
+				})
+
+				all, _ := c.Hash("CodecHashSetNX").GetAll()
+				g.Assert(all).Eql(map[string]string{"a": `"x"`, "b": `"y"`})
+
+				var s string
+				g.Assert(h.GetInto("a", &s)).Eql(nil)
+				g.Assert(s).Eql("x")
+
+				list := c.List("CodecListSet").WithCodec(JSON)
+				list.RPush("a", "b")
+				list.Set(1, "c")
+				length, _ := list.Insert(Before, "c", "d")
+				g.Assert(length).Eql(3)
+
+				raw, _ := c.List("CodecListSet").Range(0, -1)
+				g.Assert(raw).Eql([]string{`"a"`, `"d"`, `"c"`})
+			})
+
+			g.It("Encodes values of field map", func() {
+				h := c.WithCodec(JSON).Hash("CodecHashMap")
+				h.Set(map[string]interface{}{"ids": []int{1, 2}, "job": codecJob{ID: 5}})
+
+				all, _ := c.Hash("CodecHashMap").GetAll()
+				g.Assert(all).Eql(map[string]string{"ids": "[1,2]", "job": `{"ID":5,"Args":null}`})
+
+				h.Set("meta", map[string]int{"a": 1})
+				var meta map[string]int
+				g.Assert(h.GetInto("meta", &meta)).Eql(nil)
+				g.Assert(meta).Eql(map[string]int{"a": 1})
+			})
+
+			g.It("Encodes nested struct values", func() {
+				type withNested struct {
+					Name string   `redis:"name"`
+					Job  codecJob `redis:"job"`
+				}
+				h := c.Hash("CodecHashStruct").WithCodec(Gob)
+				h.SetStruct(withNested{Name: "a", Job: codecJob{ID: 4}})
+
+				name, _ := h.Get("name")
+				g.Assert(name).Eql("a")
+
+				var out withNested
+				g.Assert(h.GetStruct(&out)).Eql(nil)
+				g.Assert(out).Eql(withNested{Name: "a", Job: codecJob{ID: 4}})
+			})
+		})
+	})
+}
@@ -0,0 +1,155 @@
+package cyclone
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"sync/atomic"
+)
+
+const (
+	// DefaultCompressThreshold is a minimal size of value which is compressed.
+	DefaultCompressThreshold = 1024
+)
+
+// DefaultCompressMagic is a header of compressed values. It starts with 0xff
+// which never appears in UTF-8 text, so plain legacy values are not mistaken
+// for compressed ones.
+var DefaultCompressMagic = []byte{0xff, 'c', 'z'}
+
+// Compressor compresses and decompresses values. Gzip is built in,
+// other algorithms (e.g. zstd or snappy) can be plugged in by implementing
+// this interface.
+type Compressor interface {
+	Compress(data []byte) ([]byte, error)
+	Decompress(data []byte) ([]byte, error)
+}
+
+// Gzip compresses values with compress/gzip default compression level.
+var Gzip Compressor = gzipCompressor{level: gzip.DefaultCompression}
+
+// CompressOptions configures Compression.
+type CompressOptions struct {
+	// Threshold is a minimal size of value which is compressed, DefaultCompressThreshold
+	// when zero. Values which do not get smaller are stored uncompressed too.
+	Threshold int
+
+	// Magic is a header prepended to compressed values, DefaultCompressMagic when empty.
+	// Values without the header are read as is.
+	Magic []byte
+}
+
+// CompressionStats holds counters of Compression.
+type CompressionStats struct {
+	Compressed   int64 // number of compressed values
+	Uncompressed int64 // number of values stored uncompressed
+	BytesIn      int64 // size of compressed values before compression
+	BytesOut     int64 // size of compressed values after compression (including header)
+}
+
+// BytesSaved returns number of bytes saved by compression.
+func (s CompressionStats) BytesSaved() int64 {
+	return s.BytesIn - s.BytesOut
+}
+
+// Compression is a Transformer which compresses values larger than threshold.
+// Compressed values are prefixed with magic header, so values written before
+// compression was enabled (or smaller than threshold) are still read.
+//
+//   gz := cyclone.NewCompression(cyclone.Gzip, cyclone.CompressOptions{Threshold: 512})
+//   redis.Hash("docs").WithTransformer(gz).Set("readme", blob)
+//   log.Println(gz.Stats().BytesSaved())
+//
+type Compression struct {
+	// counters are accessed atomically, they are first to keep 64-bit alignment
+	compressed   int64
+	uncompressed int64
+	bytesIn      int64
+	bytesOut     int64
+
+	compressor Compressor
+	opts       CompressOptions
+}
+
+// NewCompression creates compression transformer.
+func NewCompression(compressor Compressor, opts CompressOptions) *Compression {
+	if opts.Threshold <= 0 {
+		opts.Threshold = DefaultCompressThreshold
+	}
+	if len(opts.Magic) == 0 {
+		opts.Magic = DefaultCompressMagic
+	}
+	return &Compression{compressor: compressor, opts: opts}
+}
+
+// Encode compresses data when it is not smaller than threshold. Smaller values
+// starting with magic header are compressed too, so they can be told apart.
+func (c *Compression) Encode(data []byte) ([]byte, error) {
+	magic := bytes.HasPrefix(data, c.opts.Magic)
+	if len(data) < c.opts.Threshold && !magic {
+		atomic.AddInt64(&c.uncompressed, 1)
+		return data, nil
+	}
+
+	compressed, err := c.compressor.Compress(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(compressed)+len(c.opts.Magic) >= len(data) && !magic {
+		atomic.AddInt64(&c.uncompressed, 1)
+		return data, nil
+	}
+
+	out := make([]byte, 0, len(c.opts.Magic)+len(compressed))
+	out = append(append(out, c.opts.Magic...), compressed...)
+	atomic.AddInt64(&c.compressed, 1)
+	atomic.AddInt64(&c.bytesIn, int64(len(data)))
+	atomic.AddInt64(&c.bytesOut, int64(len(out)))
+	return out, nil
+}
+
+// Decode decompresses data prefixed with magic header, other values are returned as is.
+func (c *Compression) Decode(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, c.opts.Magic) {
+		return data, nil
+	}
+	return c.compressor.Decompress(data[len(c.opts.Magic):])
+}
+
+// Stats returns compression counters.
+func (c *Compression) Stats() CompressionStats {
+	return CompressionStats{
+		Compressed:   atomic.LoadInt64(&c.compressed),
+		Uncompressed: atomic.LoadInt64(&c.uncompressed),
+		BytesIn:      atomic.LoadInt64(&c.bytesIn),
+		BytesOut:     atomic.LoadInt64(&c.bytesOut),
+	}
+}
+
+type gzipCompressor struct {
+	level int
+}
+
+func (g gzipCompressor) Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := gzip.NewWriterLevel(&buf, g.level)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (g gzipCompressor) Decompress(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
@@ -0,0 +1,129 @@
+package cyclone
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/franela/goblin"
+)
+
+func TestCompression(t *testing.T) {
+	g := goblin.Goblin(t)
+
+	blob := strings.Repeat(`{"name":"cyclone","tags":["redis","go"]}`, 100)
+
+	g.Describe("Compression", func() {
+		g.It("Compresses values larger than threshold", func() {
+			gz := NewCompression(Gzip, CompressOptions{Threshold: 100})
+
+			small, _ := gz.Encode([]byte("small"))
+			g.Assert(small).Eql([]byte("small"))
+
+			large, err := gz.Encode([]byte(blob))
+			g.Assert(err).Eql(nil)
+			g.Assert(bytes.HasPrefix(large, DefaultCompressMagic)).IsTrue()
+			g.Assert(len(large) < len(blob)).IsTrue()
+
+			decoded, _ := gz.Decode(large)
+			g.Assert(string(decoded)).Eql(blob)
+			decoded, _ = gz.Decode(small)
+			g.Assert(decoded).Eql([]byte("small"))
+
+			stats := gz.Stats()
+			g.Assert(stats.Compressed).Eql(int64(1))
+			g.Assert(stats.Uncompressed).Eql(int64(1))
+			g.Assert(stats.BytesIn).Eql(int64(len(blob)))
+			g.Assert(stats.BytesSaved()).Eql(int64(len(blob) - len(large)))
+		})
+
+		g.It("Compresses small values starting with magic", func() {
+			gz := NewCompression(Gzip, CompressOptions{Magic: []byte{0xff}})
+			value := []byte{0xff, 'a'}
+
+			encoded, _ := gz.Encode(value)
+			g.Assert(encoded == nil).IsFalse()
+			g.Assert(bytes.Equal(encoded, value)).IsFalse()
+
+			decoded, _ := gz.Decode(encoded)
+			g.Assert(decoded).Eql(value)
+		})
+
+		g.It("Stores incompressible values uncompressed", func() {
+			gz := NewCompression(Gzip, CompressOptions{Threshold: 1})
+
+			encoded, _ := gz.Encode([]byte("abc"))
+			g.Assert(encoded).Eql([]byte("abc"))
+		})
+	})
+
+	withConn(func(c *Cyclone) {
+		gz := NewCompression(Gzip, CompressOptions{Threshold: 100})
+
+		g.Describe(".WithTransformer", func() {
+			g.It("Compresses hash values", func() {
+				h := c.Hash("CompressHash").WithTransformer(gz)
+				h.Set("blob", blob, "small", "a")
+				h.SetNX("blob2", blob)
+				c.Hash("CompressHash").Set("legacy", "plain")
+
+				length, _ := c.Hash("CompressHash").StrLen("blob")
+				g.Assert(length < len(blob)).IsTrue()
+
+				val, _ := h.Get("blob")
+				g.Assert(val).Eql(blob)
+				val, _ = h.Get("legacy")
+				g.Assert(val).Eql("plain")
+
+				all, _ := h.GetAll()
+				g.Assert(all).Eql(map[string]string{"blob": blob, "blob2": blob, "small": "a", "legacy": "plain"})
+
+				vals, _ := h.MGet("blob", "small")
+				g.Assert(vals).Eql([]string{blob, "a"})
+
+				lookup, _ := h.MLookup("blob", "missing")
+				g.Assert(*lookup[0]).Eql(blob)
+				g.Assert(lookup[1] == nil).IsTrue()
+
+				scanned := map[string]string{}
+				for kv := range h.Scan().ChanKV(0) {
+					scanned[kv.Key] = kv.Val
+				}
+				g.Assert(scanned).Eql(all)
+			})
+
+			g.It("Compresses list elements", func() {
+				list := c.WithTransformer(gz).List("CompressList")
+				list.RPush(blob, "a", []string{"b", blob})
+
+				length, _ := c.List("CompressList").Len()
+				g.Assert(length).Eql(4)
+
+				elems, _ := list.Range(0, -1)
+				g.Assert(elems).Eql([]string{blob, "a", "b", blob})
+
+				raw, _ := c.List("CompressList").Index(0)
+				g.Assert(len(raw) < len(blob)).IsTrue()
+
+				elem, _ := list.Pop()
+				g.Assert(elem).Eql(blob)
+				elem, _ = list.RPop()
+				g.Assert(elem).Eql(blob)
+
+				var s string
+				g.Assert(list.PopInto(&s)).Eql(nil)
+				g.Assert(s).Eql("a")
+			})
+
+			g.It("Compresses values encoded with codec", func() {
+				type doc struct{ Body string }
+				h := c.Hash("CompressCodec").WithCodec(JSON).WithTransformer(gz)
+				h.Set("doc", doc{Body: blob})
+
+				var out doc
+				g.Assert(h.GetInto("doc", &out)).Eql(nil)
+				g.Assert(out.Body).Eql(blob)
+			})
+		})
+	})
+}
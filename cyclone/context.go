@@ -0,0 +1,74 @@
+package cyclone
+
+import (
+	"context"
+	"time"
+
+	"github.com/mediocregopher/radix/v3"
+)
+
+// ctxInterruptInterval is how often network deadline of a connection
+// is pushed into the past once context is done. Radix resets deadlines
+// before every read/write so a single interrupt could be missed.
+const ctxInterruptInterval = 10 * time.Millisecond
+
+// ctxClient is a radix.Client which runs every action on a dedicated
+// connection and interrupts its network I/O when ctx is done.
+type ctxClient struct {
+	radix.Client
+	ctx context.Context
+}
+
+// Do implements radix.Client.
+func (c *ctxClient) Do(a radix.Action) error {
+	if err := c.ctx.Err(); err != nil {
+		return err
+	}
+
+	var key string
+	if keys := a.Keys(); len(keys) > 0 {
+		key = keys[0]
+	}
+
+	return c.Client.Do(radix.WithConn(key, func(conn radix.Conn) error {
+		done := make(chan struct{})
+		stopped := make(chan struct{})
+		go func() {
+			defer close(stopped)
+			interruptOnDone(c.ctx, conn, done)
+		}()
+
+		err := a.Run(conn)
+		close(done)
+		<-stopped
+
+		if ctxErr := c.ctx.Err(); err != nil && ctxErr != nil {
+			return ctxErr
+		}
+		return err
+	}))
+}
+
+// interruptOnDone waits for ctx to be done and then keeps interrupting
+// conn until done is closed. Deadline is cleared before returning.
+func interruptOnDone(ctx context.Context, conn radix.Conn, done <-chan struct{}) {
+	select {
+	case <-done:
+		return
+	case <-ctx.Done():
+	}
+
+	netConn := conn.NetConn()
+	defer netConn.SetDeadline(time.Time{})
+
+	ticker := time.NewTicker(ctxInterruptInterval)
+	defer ticker.Stop()
+	for {
+		netConn.SetDeadline(time.Now())
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
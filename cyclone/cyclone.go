@@ -1,6 +1,7 @@
 package cyclone
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -10,12 +11,21 @@ import (
 
 // Cyclone wraps radix client.
 type Cyclone struct {
-	Raw *radix.Pool
+	Raw         radix.Client
+	ctx         context.Context
+	codec       Codec
+	transformer Transformer
+
+	// blocking holds dedicated connections for blocking commands,
+	// it is set only by Connect.
+	blocking *connPool
 }
 
-// DefafultPool creates default connection to redis or exists when failed.
+// DefaultPool creates default connection to redis or exits when failed.
+//
+// Deprecated: use Connect which is configurable and returns an error instead.
 func DefaultPool(n int) *radix.Pool {
-	raw, err := radix.NewPool("tcp", "127.0.0.1:6379", n)
+	raw, err := radix.NewPool("tcp", DefaultAddr, n)
 	if err != nil {
 		log.Println(err)
 		os.Exit(1)
@@ -23,34 +33,164 @@ func DefaultPool(n int) *radix.Pool {
 	return raw
 }
 
+// New creates Cyclone wrapper around any radix.Client, e.g. radix.Pool,
+// radix.Cluster, radix.Sentinel or a single radix.Conn.
+func New(client radix.Client) *Cyclone {
+	return &Cyclone{Raw: client}
+}
+
 // NewPool creates Cyclone wrapper around radix.Pool
 func NewPool(conn *radix.Pool) *Cyclone {
-	return &Cyclone{Raw: conn}
+	return New(conn)
+}
+
+// Key returns generic key wrapper.
+func (c *Cyclone) Key(key string) *Key {
+	return &Key{cyclone: c, key: key}
+}
+
+// Keyf returns generic key wrapper. Key is built from fmt.Sprintf(format, any...).
+func (c *Cyclone) Keyf(format string, any ...interface{}) *Key {
+	return &Key{cyclone: c, key: fmt.Sprintf(format, any...)}
 }
 
 // List returns list wrapper.
 func (c *Cyclone) List(key string) *List {
-	list := List{cyclone: c, key: key}
+	list := List{Key: Key{cyclone: c, key: key}}
 	return &list
 }
 
 // Listf returns list wrapper. Key is built from fmt.Sprintf(format, any...).
 func (c *Cyclone) Listf(format string, any ...interface{}) *List {
-	list := List{cyclone: c, key: fmt.Sprintf(format, any...)}
+	list := List{Key: Key{cyclone: c, key: fmt.Sprintf(format, any...)}}
 	return &list
 }
 
 // Hash returns Hash wrapper.
 func (c *Cyclone) Hash(key string) *Hash {
-	return &Hash{cyclone: c, key: key}
+	return &Hash{Key: Key{cyclone: c, key: key}}
 }
 
 // Hashf returns Hash wrapper. Key is built from fmt.Sprintf(format, any...).
 func (c *Cyclone) Hashf(format string, any ...interface{}) *Hash {
-	return &Hash{cyclone: c, key: fmt.Sprintf(format, any...)}
+	return &Hash{Key: Key{cyclone: c, key: fmt.Sprintf(format, any...)}}
+}
+
+// Set returns Set wrapper.
+func (c *Cyclone) Set(key string) *Set {
+	return &Set{Key: Key{cyclone: c, key: key}}
+}
+
+// Setf returns Set wrapper. Key is built from fmt.Sprintf(format, any...).
+func (c *Cyclone) Setf(format string, any ...interface{}) *Set {
+	return &Set{Key: Key{cyclone: c, key: fmt.Sprintf(format, any...)}}
+}
+
+// Stream returns Stream wrapper.
+func (c *Cyclone) Stream(key string) *Stream {
+	return &Stream{Key: Key{cyclone: c, key: key}}
+}
+
+// Streamf returns Stream wrapper. Key is built from fmt.Sprintf(format, any...).
+func (c *Cyclone) Streamf(format string, any ...interface{}) *Stream {
+	return &Stream{Key: Key{cyclone: c, key: fmt.Sprintf(format, any...)}}
+}
+
+// String returns String wrapper.
+func (c *Cyclone) String(key string) *String {
+	return &String{Key: Key{cyclone: c, key: key}}
+}
+
+// Stringf returns String wrapper. Key is built from fmt.Sprintf(format, any...).
+func (c *Cyclone) Stringf(format string, any ...interface{}) *String {
+	return &String{Key: Key{cyclone: c, key: fmt.Sprintf(format, any...)}}
+}
+
+// ZSet returns ZSet wrapper.
+func (c *Cyclone) ZSet(key string) *ZSet {
+	return &ZSet{Key: Key{cyclone: c, key: key}}
+}
+
+// ZSetf returns ZSet wrapper. Key is built from fmt.Sprintf(format, any...).
+func (c *Cyclone) ZSetf(format string, any ...interface{}) *ZSet {
+	return &ZSet{Key: Key{cyclone: c, key: fmt.Sprintf(format, any...)}}
+}
+
+// WithContext returns a shallow copy of Cyclone with its context changed to ctx.
+// Every command issued through the returned Cyclone (and wrappers created from it)
+// honors cancellation and deadline of ctx.
+func (c *Cyclone) WithContext(ctx context.Context) *Cyclone {
+	if ctx == nil {
+		panic("cyclone: nil context")
+	}
+	cc := *c
+	cc.ctx = ctx
+	return &cc
+}
+
+// WithCodec returns a shallow copy of Cyclone with its codec changed to codec.
+// Values written by Hash.Set, Hash.SetNX, List pushes, List.Set and List.Insert
+// issued through the returned Cyclone (and wrappers created from it) are encoded
+// with codec and Into methods (e.g. List.PopInto) decode them back. Nil codec
+// restores radix formatting.
+func (c *Cyclone) WithCodec(codec Codec) *Cyclone {
+	cc := *c
+	cc.codec = codec
+	return &cc
+}
+
+// Codec returns Cyclone's codec, nil when values are formatted by radix.
+func (c *Cyclone) Codec() Codec {
+	return c.codec
+}
+
+// WithTransformer returns a shallow copy of Cyclone with its transformer changed to t.
+// Values of Hash and List wrappers issued through the returned Cyclone are transformed
+// by t after they are encoded and restored when they are read. Elements matched by redis
+// (List.Rem, List.Pos and pivot of List.Insert) are not transformed.
+// Nil transformer disables transformation.
+func (c *Cyclone) WithTransformer(t Transformer) *Cyclone {
+	cc := *c
+	cc.transformer = t
+	return &cc
+}
+
+// Transformer returns Cyclone's transformer, nil when values are not transformed.
+func (c *Cyclone) Transformer() Transformer {
+	return c.transformer
+}
+
+// Context returns Cyclone's context. Returned context is always non-nil,
+// it defaults to the background context.
+func (c *Cyclone) Context() context.Context {
+	if c.ctx != nil {
+		return c.ctx
+	}
+	return context.Background()
 }
 
 // Close closes current connection.
 func (c *Cyclone) Close() {
+	if c.blocking != nil {
+		c.blocking.close()
+	}
 	c.Raw.Close()
 }
+
+// do performs action translating redis error replies into cyclone errors.
+func (c *Cyclone) do(a radix.Action) error {
+	return wrapErr(c.client().Do(a))
+}
+
+// client returns radix client bound to Cyclone's context.
+func (c *Cyclone) client() radix.Client {
+	return c.bind(c.Raw)
+}
+
+// bind binds client to Cyclone's context.
+func (c *Cyclone) bind(client radix.Client) radix.Client {
+	if c.ctx == nil || c.ctx.Done() == nil {
+		return client
+	}
+	return &ctxClient{ctx: c.ctx, Client: client}
+}
@@ -0,0 +1,103 @@
+package cyclone
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/franela/goblin"
+	"github.com/mediocregopher/radix/v3"
+)
+
+func TestCyclone(t *testing.T) {
+	g := goblin.Goblin(t)
+
+	g.Describe(".New", func() {
+		g.It("Accepts radix.Conn test double", func() {
+			hash := map[string]string{}
+			stub := radix.Stub("tcp", "127.0.0.1:6379", func(args []string) interface{} {
+				switch args[0] {
+				case "HSET":
+					hash[args[2]] = args[3]
+					return 1
+				case "HGET":
+					return hash[args[2]]
+				}
+				return nil
+			})
+			c := New(stub)
+			defer c.Close()
+
+			added, err := c.Hash("CycloneNewStub").Set("a", "1")
+			g.Assert(err).Eql(nil)
+			g.Assert(added).Eql(1)
+
+			val, _ := c.Hash("CycloneNewStub").Get("a")
+			g.Assert(val).Eql("1")
+		})
+
+		g.It("Accepts single connection", func() {
+			conn, err := radix.Dial("tcp", fmt.Sprintf("%s:%s", os.Getenv("REDIS_HOST"), os.Getenv("REDIS_PORT")))
+			g.Assert(err).Eql(nil)
+			c := New(conn)
+			defer c.Close()
+
+			length, _ := c.List("CycloneNewConn").Push("a", "b")
+			g.Assert(length).Eql(2)
+			c.Raw.Do(radix.Cmd(nil, "DEL", "CycloneNewConn"))
+		})
+	})
+
+	withConn(func(c *Cyclone) {
+		g.Describe(".WithContext", func() {
+			g.It("Returns context error for cancelled context", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				_, err := c.WithContext(ctx).Hash("CycloneCtxCancelled").Set("a", "1")
+				g.Assert(err).Eql(context.Canceled)
+
+				exists, _ := c.Hash("CycloneCtxCancelled").Exists("a")
+				g.Assert(exists).IsFalse()
+			})
+
+			g.It("Interrupts in-flight command when deadline passes", func() {
+				ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+				defer cancel()
+
+				start := time.Now()
+				err := c.WithContext(ctx).do(radix.Cmd(nil, "BLPOP", "CycloneCtxBlocking", "5"))
+				g.Assert(err).Eql(context.DeadlineExceeded)
+				g.Assert(time.Since(start) < time.Second).IsTrue()
+			})
+
+			g.It("Keeps original Cyclone context", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+
+				g.Assert(c.WithContext(ctx).Context()).Eql(ctx)
+				g.Assert(c.Context()).Eql(context.Background())
+			})
+
+			g.It("Stops ChanKV iteration when cancelled", func() {
+				for i := 0; i < 100; i++ {
+					c.Raw.Do(radix.Cmd(nil, "HSET", "CycloneCtxScan", strconv.Itoa(i), strconv.Itoa(i)))
+				}
+				ctx, cancel := context.WithCancel(context.Background())
+
+				ch := c.WithContext(ctx).Hash("CycloneCtxScan").Scan().Count(10).ChanKV(0)
+				<-ch
+				cancel()
+
+				received := 1
+				for range ch {
+					received++
+				}
+				g.Assert(received < 100).IsTrue()
+			})
+		})
+	})
+}
@@ -0,0 +1,127 @@
+package cyclone
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// encryptMagic marks encrypted values. It is a fixed part of the encrypted
+// format and is authenticated with the key ID, the leading 0xff is never
+// valid UTF-8 so plaintext is told apart when AllowPlaintext is set.
+var encryptMagic = []byte{0xff, 'e', 'n'}
+
+var (
+	// ErrUnknownKey is returned when a value is encrypted with key ID
+	// which is not known to Encryption.
+	ErrUnknownKey = errors.New("cyclone: unknown encryption key")
+
+	// ErrInvalidCiphertext is returned when encrypted value is malformed.
+	ErrInvalidCiphertext = errors.New("cyclone: invalid ciphertext")
+)
+
+// Encryption is a Transformer which encrypts values with AEAD (e.g. AES-GCM).
+// Every value is sealed with the current key under a random nonce and stores
+// ID of the key, so values encrypted with older keys are still read after
+// the current key is rotated:
+//
+//   enc, err := cyclone.NewEncryption("2021-02", map[string]cipher.AEAD{
+//     "2021-01": oldKey,
+//     "2021-02": newKey,
+//   })
+//   users := redis.WithTransformer(enc)
+//   users.Hash("user:1").Set("email", "bob@example.com")
+//
+// Encrypted value is magic header, key ID length (1 byte), key ID, nonce
+// and sealed value. Header with key ID is authenticated as additional data.
+// Values without magic header are rejected with ErrInvalidCiphertext unless
+// AllowPlaintext is set.
+type Encryption struct {
+	// AllowPlaintext makes Decode return values without magic header as is,
+	// e.g. while values written before encryption was enabled are migrated.
+	// Plaintext values are not authenticated then.
+	AllowPlaintext bool
+
+	current string
+	keys    map[string]cipher.AEAD
+}
+
+// NewAESGCM creates AES-GCM AEAD from 16, 24 or 32 bytes long key
+// (AES-128, AES-192 or AES-256).
+func NewAESGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// NewEncryption creates encryption transformer which encrypts values with key
+// identified by current and decrypts values encrypted with any of keys.
+// Key IDs must be 1 to 255 bytes long.
+func NewEncryption(current string, keys map[string]cipher.AEAD) (*Encryption, error) {
+	e := &Encryption{current: current, keys: make(map[string]cipher.AEAD, len(keys))}
+	for id, aead := range keys {
+		if len(id) == 0 || len(id) > 255 {
+			return nil, fmt.Errorf("cyclone: invalid encryption key ID %q", id)
+		}
+		e.keys[id] = aead
+	}
+	if _, ok := e.keys[current]; !ok {
+		return nil, ErrUnknownKey
+	}
+	return e, nil
+}
+
+// Encode encrypts data with the current key.
+func (e *Encryption) Encode(data []byte) ([]byte, error) {
+	aead := e.keys[e.current]
+	header := e.header(e.current)
+
+	out := make([]byte, len(header)+aead.NonceSize(), len(header)+aead.NonceSize()+len(data)+aead.Overhead())
+	copy(out, header)
+	nonce := out[len(header):]
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(out, nonce, data, header), nil
+}
+
+// Decode decrypts data prefixed with magic header. Other values are returned as is
+// when AllowPlaintext is set, ErrInvalidCiphertext is returned for them otherwise.
+func (e *Encryption) Decode(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, encryptMagic) {
+		if e.AllowPlaintext {
+			return data, nil
+		}
+		return nil, ErrInvalidCiphertext
+	}
+
+	rest := data[len(encryptMagic):]
+	if len(rest) == 0 || len(rest) < 1+int(rest[0]) {
+		return nil, ErrInvalidCiphertext
+	}
+	id := string(rest[1 : 1+rest[0]])
+	aead, ok := e.keys[id]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+
+	header := data[:len(encryptMagic)+1+len(id)]
+	sealed := data[len(header):]
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrInvalidCiphertext
+	}
+	return aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], header)
+}
+
+// header returns header of value encrypted with key id.
+func (e *Encryption) header(id string) []byte {
+	header := make([]byte, 0, len(encryptMagic)+1+len(id))
+	header = append(header, encryptMagic...)
+	header = append(header, byte(len(id)))
+	return append(header, id...)
+}
@@ -0,0 +1,165 @@
+package cyclone
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"testing"
+
+	"github.com/franela/goblin"
+)
+
+func TestEncryption(t *testing.T) {
+	g := goblin.Goblin(t)
+
+	oldKey, _ := NewAESGCM(bytes.Repeat([]byte{1}, 32))
+	newKey, _ := NewAESGCM(bytes.Repeat([]byte{2}, 16))
+
+	g.Describe("Encryption", func() {
+		g.It("Encrypts and decrypts values", func() {
+			enc, err := NewEncryption("k1", map[string]cipher.AEAD{"k1": oldKey})
+			g.Assert(err).Eql(nil)
+
+			a, _ := enc.Encode([]byte("secret"))
+			b, _ := enc.Encode([]byte("secret"))
+			g.Assert(bytes.HasPrefix(a, encryptMagic)).IsTrue()
+			g.Assert(bytes.Contains(a, []byte("secret"))).IsFalse()
+			g.Assert(bytes.Equal(a, b)).IsFalse()
+
+			plain, err := enc.Decode(a)
+			g.Assert(err).Eql(nil)
+			g.Assert(plain).Eql([]byte("secret"))
+
+			_, err = enc.Decode([]byte("legacy"))
+			g.Assert(err).Eql(ErrInvalidCiphertext)
+		})
+
+		g.It("Reads plaintext values when allowed", func() {
+			enc, _ := NewEncryption("k1", map[string]cipher.AEAD{"k1": oldKey})
+			enc.AllowPlaintext = true
+
+			plain, err := enc.Decode([]byte("legacy"))
+			g.Assert(err).Eql(nil)
+			g.Assert(plain).Eql([]byte("legacy"))
+
+			encrypted, _ := enc.Encode([]byte("secret"))
+			plain, _ = enc.Decode(encrypted)
+			g.Assert(plain).Eql([]byte("secret"))
+		})
+
+		g.It("Reads values encrypted with rotated keys", func() {
+			before, _ := NewEncryption("k1", map[string]cipher.AEAD{"k1": oldKey})
+			after, _ := NewEncryption("k2", map[string]cipher.AEAD{"k1": oldKey, "k2": newKey})
+			only, _ := NewEncryption("k2", map[string]cipher.AEAD{"k2": newKey})
+
+			encrypted, _ := before.Encode([]byte("secret"))
+			plain, err := after.Decode(encrypted)
+			g.Assert(err).Eql(nil)
+			g.Assert(plain).Eql([]byte("secret"))
+
+			_, err = only.Decode(encrypted)
+			g.Assert(err).Eql(ErrUnknownKey)
+		})
+
+		g.It("Rejects tampered values", func() {
+			enc, _ := NewEncryption("k1", map[string]cipher.AEAD{"k1": oldKey})
+			encrypted, _ := enc.Encode([]byte("secret"))
+
+			encrypted[len(encrypted)-1] ^= 1
+			_, err := enc.Decode(encrypted)
+			g.Assert(err == nil).IsFalse()
+
+			_, err = enc.Decode(encrypted[:len(encryptMagic)+1])
+			g.Assert(err).Eql(ErrInvalidCiphertext)
+		})
+
+		g.It("Validates keys", func() {
+			_, err := NewEncryption("missing", map[string]cipher.AEAD{"k1": oldKey})
+			g.Assert(err).Eql(ErrUnknownKey)
+
+			_, err = NewEncryption("", map[string]cipher.AEAD{"": oldKey})
+			g.Assert(err == nil).IsFalse()
+
+			_, err = NewAESGCM([]byte("short"))
+			g.Assert(err == nil).IsFalse()
+		})
+	})
+
+	withConn(func(c *Cyclone) {
+		enc, _ := NewEncryption("k1", map[string]cipher.AEAD{"k1": oldKey})
+
+		g.Describe(".WithTransformer", func() {
+			g.It("Encrypts hash values", func() {
+				h := c.Hash("EncryptHash").WithTransformer(enc)
+				h.Set("email", "bob@example.com", "phone", "123")
+
+				raw, _ := c.Hash("EncryptHash").Get("email")
+				g.Assert(raw == "bob@example.com").IsFalse()
+
+				val, _ := h.Get("email")
+				g.Assert(val).Eql("bob@example.com")
+
+				vals, _ := h.MGet("email", "phone")
+				g.Assert(vals).Eql([]string{"bob@example.com", "123"})
+
+				all, _ := h.GetAll()
+				g.Assert(all).Eql(map[string]string{"email": "bob@example.com", "phone": "123"})
+
+				it := h.Scan().Iter()
+				scanned := map[string]string{}
+				for it.Next() {
+					scanned[it.Field()] = it.Value()
+				}
+				g.Assert(it.Err()).Eql(nil)
+				g.Assert(scanned).Eql(all)
+			})
+
+			g.It("Reads missing fields", func() {
+				h := c.Hash("EncryptHashMissing").WithTransformer(enc)
+				h.Set("email", "bob@example.com")
+
+				val, err := h.Get("missing")
+				g.Assert(err).Eql(nil)
+				g.Assert(val).Eql("")
+
+				vals, err := h.MGet("email", "missing")
+				g.Assert(err).Eql(nil)
+				g.Assert(vals).Eql([]string{"bob@example.com", ""})
+
+				var get *StringResult
+				var mget *StringsResult
+				err = c.WithTransformer(enc).Pipeline(func(p *Pipe) {
+					get = p.Hash("EncryptHashMissing").Get("missing")
+					mget = p.Hash("EncryptHashMissing").MGet("email", "missing")
+				})
+				g.Assert(err).Eql(nil)
+				val, err = get.Val()
+				g.Assert(err).Eql(nil)
+				g.Assert(val).Eql("")
+				vals, err = mget.Val()
+				g.Assert(err).Eql(nil)
+				g.Assert(vals).Eql([]string{"bob@example.com", ""})
+			})
+
+			g.It("Reports decryption errors", func() {
+				other, _ := NewEncryption("k2", map[string]cipher.AEAD{"k2": newKey})
+				c.Hash("EncryptHashOther").WithTransformer(enc).Set("email", "bob@example.com")
+
+				h := c.Hash("EncryptHashOther").WithTransformer(other)
+				_, err := h.Get("email")
+				g.Assert(err).Eql(ErrUnknownKey)
+
+				it := h.Scan()
+				for range it.ChanKV(0) {
+				}
+				g.Assert(it.Err()).Eql(ErrUnknownKey)
+			})
+
+			g.It("Rejects unencrypted values", func() {
+				c.Hash("EncryptHashPlain").Set("email", "bob@example.com")
+
+				_, err := c.Hash("EncryptHashPlain").WithTransformer(enc).Get("email")
+				g.Assert(err).Eql(ErrInvalidCiphertext)
+			})
+		})
+	})
+}
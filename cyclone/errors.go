@@ -0,0 +1,85 @@
+package cyclone
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mediocregopher/radix/v3"
+	"github.com/mediocregopher/radix/v3/resp/resp2"
+)
+
+var (
+	// ErrNil is returned when redis replies with nil, e.g. when popping
+	// from an empty list or reading an index that is out of range.
+	ErrNil = errors.New("cyclone: nil reply")
+
+	// ErrWrongType is returned when an operation is performed against
+	// a key holding the wrong kind of value (WRONGTYPE reply).
+	ErrWrongType = errors.New("cyclone: operation against a key holding the wrong kind of value")
+
+	// ErrNoSuchKey is returned when an operation requires an existing key
+	// but the key does not exist (e.g. LSET on a missing list).
+	ErrNoSuchKey = errors.New("cyclone: no such key")
+
+	// ErrIndexOutOfRange is returned when an index passed to redis
+	// is out of range (e.g. LSET with index past the end of the list).
+	ErrIndexOutOfRange = errors.New("cyclone: index out of range")
+
+	// ErrBusyGroup is returned when creating a stream consumer group
+	// that already exists (BUSYGROUP reply).
+	ErrBusyGroup = errors.New("cyclone: consumer group already exists")
+
+	// ErrNoGroup is returned when a stream or its consumer group
+	// does not exist (NOGROUP reply).
+	ErrNoGroup = errors.New("cyclone: no such stream or consumer group")
+)
+
+// FieldError is returned when hash field value cannot be converted
+// from or into Go value.
+type FieldError struct {
+	Field string
+	Value string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("cyclone: field %s: %v", e.Field, e.Err)
+}
+
+// Unwrap returns underlying parse error.
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// wrapErr translates well known redis error replies into sentinel errors.
+// Any other error (connection failures, unknown replies) is returned as is.
+func wrapErr(err error) error {
+	var respErr resp2.Error
+	if err == nil || !errors.As(err, &respErr) {
+		return err
+	}
+
+	msg := respErr.Error()
+	switch {
+	case strings.HasPrefix(msg, "WRONGTYPE"):
+		return ErrWrongType
+	case msg == "ERR no such key":
+		return ErrNoSuchKey
+	case msg == "ERR index out of range":
+		return ErrIndexOutOfRange
+	case strings.HasPrefix(msg, "BUSYGROUP"):
+		return ErrBusyGroup
+	case strings.HasPrefix(msg, "NOGROUP"):
+		return ErrNoGroup
+	}
+	return err
+}
+
+// nilErr returns ErrNil when reply wrapped in mn was nil, err otherwise.
+func nilErr(mn *radix.MaybeNil, err error) error {
+	if err == nil && mn.Nil {
+		return ErrNil
+	}
+	return err
+}
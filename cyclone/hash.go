@@ -1,23 +1,45 @@
 package cyclone
 
 import (
+	"bufio"
+	"encoding/json"
+	"reflect"
 	"strconv"
+	"time"
 
 	"github.com/mediocregopher/radix/v3"
+	"github.com/mediocregopher/radix/v3/resp/resp2"
 )
 
 // Hash wraps redis hash operations.
 type Hash struct {
-	cyclone *Cyclone
-	key     string
+	Key
 }
 
 // HashScanIterator allows for channel based iteration.
 type HashScanIterator struct {
+	scanCtl
 	hash *Hash
 	opts radix.ScanOpts
 }
 
+// HashIter allows for pull based iteration without goroutines.
+type HashIter struct {
+	pager  scanPager
+	page   []string
+	field  string
+	value  string
+	err    error
+	cursor string
+}
+
+// HashPages allows for pull based iteration one HSCAN page at a time.
+type HashPages struct {
+	pager  scanPager
+	fields []HashField
+	err    error
+}
+
 // HashField is used in ChanKV iterator as a channel type.
 type HashField struct {
 	Key string
@@ -27,11 +49,12 @@ type HashField struct {
 // Del removes the specified fields from the hash stored at key. Specified
 // fields that do not exist within this hash are ignored. If key does
 // not exist, it is treated as an empty hash and this command returns 0.
+// Use Key.Del to remove the whole hash.
 // https://redis.io/commands/hdel
 //
 // Time complexity: O(N) where N is the number of fields to be removed.
 func (l *Hash) Del(fields ...interface{}) (deletedKeys int, err error) {
-	err = l.cyclone.Raw.Do(radix.FlatCmd(
+	err = l.cyclone.do(radix.FlatCmd(
 		&deletedKeys,
 		"HDEL",
 		l.key,
@@ -41,21 +64,24 @@ func (l *Hash) Del(fields ...interface{}) (deletedKeys int, err error) {
 }
 
 // Exists returns if field is an existing field in the hash stored at key.
+// Use Key.Exists to check the hash itself.
 // https://redis.io/commands/hexists
 //
 // Time complexity: O(1)
 func (l *Hash) Exists(field string) (bool, error) {
 	var exists int
-	err := l.cyclone.Raw.Do(radix.Cmd(&exists, "HEXISTS", l.key, field))
+	err := l.cyclone.do(radix.Cmd(&exists, "HEXISTS", l.key, field))
 	return exists == 1, err
 }
 
 // Get returns the value associated with field in the hash stored at key.
+// Empty string is returned when field does not exist, use Lookup to tell
+// missing field from empty value.
 // https://redis.io/commands/hget
 //
 // Time complexity: O(1)
 func (l *Hash) Get(field string) (value string, err error) {
-	err = l.cyclone.Raw.Do(radix.Cmd(&value, "HGET", l.key, field))
+	value, _, err = l.Lookup(field)
 	return
 }
 
@@ -66,7 +92,120 @@ func (l *Hash) Get(field string) (value string, err error) {
 //
 // Time complexity: O(N) where N is the size of the hash.
 func (l *Hash) GetAll() (all map[string]string, err error) {
-	err = l.cyclone.Raw.Do(radix.Cmd(&all, "HGETALL", l.key))
+	if err = l.cyclone.do(radix.Cmd(&all, "HGETALL", l.key)); err != nil || l.cyclone.transformer == nil {
+		return
+	}
+	for field, value := range all {
+		if all[field], err = l.cyclone.restore(value); err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
+// GetBool returns the value associated with field parsed as bool
+// (1, t, true, 0, f, false...). ErrNil is returned when field does not exist
+// and *FieldError when value cannot be parsed.
+// https://redis.io/commands/hget
+//
+// Time complexity: O(1)
+func (l *Hash) GetBool(field string) (value bool, err error) {
+	err = l.getParsed(field, &value)
+	return
+}
+
+// GetBytes returns the value associated with field as bytes.
+// ErrNil is returned when field does not exist.
+// https://redis.io/commands/hget
+//
+// Time complexity: O(1)
+func (l *Hash) GetBytes(field string) (value []byte, err error) {
+	mn := radix.MaybeNil{Rcv: &value}
+	if err = nilErr(&mn, l.cyclone.do(radix.Cmd(&mn, "HGET", l.key, field))); err != nil || l.cyclone.transformer == nil {
+		return
+	}
+	return l.cyclone.transformer.Decode(value)
+}
+
+// GetDuration returns the value associated with field parsed by time.ParseDuration
+// (e.g. 1m30s). ErrNil is returned when field does not exist and *FieldError when value
+// cannot be parsed.
+// https://redis.io/commands/hget
+//
+// Time complexity: O(1)
+func (l *Hash) GetDuration(field string) (value time.Duration, err error) {
+	err = l.getParsed(field, &value)
+	return
+}
+
+// GetFloat returns the value associated with field parsed as float64.
+// ErrNil is returned when field does not exist and *FieldError when value
+// cannot be parsed.
+// https://redis.io/commands/hget
+//
+// Time complexity: O(1)
+func (l *Hash) GetFloat(field string) (value float64, err error) {
+	err = l.getParsed(field, &value)
+	return
+}
+
+// GetInt returns the value associated with field parsed as int.
+// ErrNil is returned when field does not exist and *FieldError when value
+// cannot be parsed.
+// https://redis.io/commands/hget
+//
+// Time complexity: O(1)
+func (l *Hash) GetInt(field string) (value int, err error) {
+	err = l.getParsed(field, &value)
+	return
+}
+
+// GetInt64 returns the value associated with field parsed as int64.
+// ErrNil is returned when field does not exist and *FieldError when value
+// cannot be parsed.
+// https://redis.io/commands/hget
+//
+// Time complexity: O(1)
+func (l *Hash) GetInt64(field string) (value int64, err error) {
+	err = l.getParsed(field, &value)
+	return
+}
+
+// GetInto decodes the value associated with field into v with codec
+// (or radix when codec is not set), see WithCodec. ErrNil is returned
+// when field does not exist.
+// https://redis.io/commands/hget
+//
+// Time complexity: O(1)
+func (l *Hash) GetInto(field string, v interface{}) error {
+	return l.cyclone.doInto(v, "HGET", l.key, field)
+}
+
+// GetJSON decodes JSON value associated with field into v.
+// ErrNil is returned when field does not exist and *FieldError when value
+// cannot be decoded.
+// https://redis.io/commands/hget
+//
+// Time complexity: O(1)
+func (l *Hash) GetJSON(field string, v interface{}) error {
+	value, err := l.GetBytes(field)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(value, v); err != nil {
+		return &FieldError{Field: field, Value: string(value), Err: err}
+	}
+	return nil
+}
+
+// GetTime returns the value associated with field parsed as RFC 3339 time.
+// ErrNil is returned when field does not exist and *FieldError when value
+// cannot be parsed.
+// https://redis.io/commands/hget
+//
+// Time complexity: O(1)
+func (l *Hash) GetTime(field string) (value time.Time, err error) {
+	err = l.getParsed(field, &value)
 	return
 }
 
@@ -77,7 +216,7 @@ func (l *Hash) GetAll() (all map[string]string, err error) {
 //
 // Time complexity: O(1)
 func (l *Hash) Incr(field string, by int) (valAfterIncr int, err error) {
-	err = l.cyclone.Raw.Do(radix.Cmd(
+	err = l.cyclone.do(radix.Cmd(
 		&valAfterIncr,
 		"HINCRBY",
 		l.key,
@@ -95,7 +234,7 @@ func (l *Hash) Incr(field string, by int) (valAfterIncr int, err error) {
 //
 // Time complexity: O(1)
 func (l *Hash) IncrFloat(field string, by float64) (valAfterIncr float64, err error) {
-	err = l.cyclone.Raw.Do(radix.Cmd(
+	err = l.cyclone.do(radix.Cmd(
 		&valAfterIncr,
 		"HINCRBYFLOAT",
 		l.key,
@@ -110,7 +249,7 @@ func (l *Hash) IncrFloat(field string, by float64) (valAfterIncr float64, err er
 //
 // Time complexity: O(N) where N is the size of the hash.
 func (l *Hash) Keys() (keys []string, err error) {
-	err = l.cyclone.Raw.Do(radix.Cmd(&keys, "HKEYS", l.key))
+	err = l.cyclone.do(radix.Cmd(&keys, "HKEYS", l.key))
 	return
 }
 
@@ -119,25 +258,60 @@ func (l *Hash) Keys() (keys []string, err error) {
 //
 // Time complexity: O(1)
 func (l *Hash) Len() (keyCount int, err error) {
-	err = l.cyclone.Raw.Do(radix.Cmd(&keyCount, "HLEN", l.key))
+	err = l.cyclone.do(radix.Cmd(&keyCount, "HLEN", l.key))
 	return
 }
 
+// Lookup returns the value associated with field in the hash stored at key.
+// ok is false when field does not exist.
+// https://redis.io/commands/hget
+//
+// Time complexity: O(1)
+func (l *Hash) Lookup(field string) (value string, ok bool, err error) {
+	mn := radix.MaybeNil{Rcv: &value}
+	if err = l.cyclone.do(radix.Cmd(&mn, "HGET", l.key, field)); err != nil || mn.Nil {
+		return "", false, err
+	}
+	value, err = l.cyclone.restore(value)
+	return value, err == nil, err
+}
+
 // MGet returns the values associated with the specified fields in the hash
 // stored at key. For every field that does not exist in the hash, a nil
 // value is returned. Because non-existing keys are treated as empty hashes,
 // running HMGET against a non-existing key will return a list of nil values.
+// Nil values are returned as empty strings, use MLookup to tell missing fields
+// from empty values.
 // https://redis.io/commands/hmget
 //
 // Time complexity: O(N) where N is the number of fields being requested.
-func (l *Hash) MGet(fields ...interface{}) (values []string, err error) {
-	err = l.cyclone.Raw.Do(radix.FlatCmd(
-		&values,
+func (l *Hash) MGet(fields ...interface{}) ([]string, error) {
+	lookup, err := l.MLookup(fields...)
+	if err != nil {
+		return nil, err
+	}
+	return nullStrings(lookup).strings(), nil
+}
+
+// MLookup is equal to MGet, but it returns nil for every field that does not exist.
+// https://redis.io/commands/hmget
+//
+// Time complexity: O(N) where N is the number of fields being requested.
+func (l *Hash) MLookup(fields ...interface{}) (values []*string, err error) {
+	var reply nullStrings
+	err = l.cyclone.do(radix.FlatCmd(
+		&reply,
 		"HMGET",
 		l.key,
 		fields...,
 	))
-	return
+	if err == nil {
+		err = reply.restore(l.cyclone)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return reply, nil
 }
 
 // Scan iterates fields of Hash types and their associated values.
@@ -153,20 +327,18 @@ func (l *Hash) Scan() *HashScanIterator {
 
 // Set sets field in the hash stored at key to value. If key does not exist,
 // a new key holding a hash is created. If field already exists in the hash,
-// it is overwritten.
+// it is overwritten. Values are encoded with codec when set, see WithCodec.
 // https://redis.io/commands/hset
 //
 // Time complexity: O(1) for each field/value pair added, so O(N) to add N
 //                  field/value pairs when the command is called with multiple
 //                  field/value pairs.
 func (l *Hash) Set(kvpairs ...interface{}) (addedFields int, err error) {
-	err = l.cyclone.Raw.Do(radix.FlatCmd(
-		&addedFields,
-		"HSET",
-		l.key,
-		kvpairs...,
-	))
-	return
+	kvpairs, err = l.cyclone.encode(kvpairs, 1, 2)
+	if err != nil {
+		return 0, err
+	}
+	return l.set(kvpairs)
 }
 
 // SetNX sets field in the hash stored at key to value, only if field does not yet exist.
@@ -176,8 +348,12 @@ func (l *Hash) Set(kvpairs ...interface{}) (addedFields int, err error) {
 //
 // Time complexity: O(1)
 func (l *Hash) SetNX(k, v string) (bool, error) {
+	value, err := l.cyclone.encodeValue(v)
+	if err != nil {
+		return false, err
+	}
 	var wasSet int
-	err := l.cyclone.Raw.Do(radix.Cmd(&wasSet, "HSETNX", l.key, k, v))
+	err = l.cyclone.do(radix.Cmd(&wasSet, "HSETNX", l.key, k, value))
 	return wasSet == 1, err
 }
 
@@ -187,7 +363,7 @@ func (l *Hash) SetNX(k, v string) (bool, error) {
 //
 // Time complexity: O(1)
 func (l *Hash) StrLen(field string) (length int, err error) {
-	err = l.cyclone.Raw.Do(radix.Cmd(&length, "HSTRLEN", l.key, field))
+	err = l.cyclone.do(radix.Cmd(&length, "HSTRLEN", l.key, field))
 	return
 }
 
@@ -196,10 +372,27 @@ func (l *Hash) StrLen(field string) (length int, err error) {
 //
 // Time complexity: O(N) where N is the size of the hash.
 func (l *Hash) Vals() (values []string, err error) {
-	err = l.cyclone.Raw.Do(radix.Cmd(&values, "HVALS", l.key))
+	err = l.cyclone.do(radix.Cmd(&values, "HVALS", l.key))
+	if err == nil {
+		err = l.cyclone.restoreAll(values)
+	}
 	return
 }
 
+// WithCodec returns copy of the hash wrapper which encodes values set by Set
+// and SetNX with codec and decodes them in GetInto. Codec is also used for
+// nested values in SetStruct and GetStruct.
+func (l *Hash) WithCodec(codec Codec) *Hash {
+	return &Hash{Key: Key{cyclone: l.cyclone.WithCodec(codec), key: l.key}}
+}
+
+// WithTransformer returns copy of the hash wrapper which transforms written values
+// with t and restores values read by all methods including scan iterators,
+// see Cyclone.WithTransformer.
+func (l *Hash) WithTransformer(t Transformer) *Hash {
+	return &Hash{Key: Key{cyclone: l.cyclone.WithTransformer(t), key: l.key}}
+}
+
 // Count sets count hint for iterator. Redis default hint is 10 when not specified.
 // https://redis.io/commands/scan#the-count-option
 //
@@ -216,66 +409,229 @@ func (i *HashScanIterator) Match(pattern string) *HashScanIterator {
 	return i
 }
 
+// From sets cursor to start iteration from, e.g. one returned by Cursor
+// of an interrupted iteration. Elements may be returned more than once.
+func (i *HashScanIterator) From(cursor string) *HashScanIterator {
+	i.setCursor(cursor)
+	return i
+}
+
 // Chan returns channel and starts iteration.
-// It will send Key/Values separately.
+// It will send Key/Values separately. Iteration stops and the channel is closed
+// when Cyclone's context is done or Stop is called, check Err after the channel
+// is closed.
 func (i *HashScanIterator) Chan(bufferSize int) <-chan string {
 	ch := make(chan string, bufferSize)
+	pager := i.pager()
 
 	go func() {
 		defer close(ch)
-
-		i.opts.Command = "HSCAN"
-		i.opts.Key = i.hash.key
-
-		scanner := radix.NewScanner(i.hash.cyclone.Raw, i.opts)
-		defer func() {
-			if err := scanner.Close(); err != nil {
-				// TODO: handle error
-			}
-		}()
-
-		var key string
-		for scanner.Next(&key) {
-			ch <- key
-		}
+		scanChan(pager, &i.scanCtl, sendStrings(ch))
 	}()
 	return ch
 }
 
 // ChanKV returns channel and starts iteration.
-// It will send HashField struct containing Key and Val.
+// It will send HashField struct containing Key and Val. Iteration stops and
+// the channel is closed when Cyclone's context is done or Stop is called,
+// check Err after the channel is closed.
 func (i *HashScanIterator) ChanKV(bufferSize int) <-chan HashField {
 	ch := make(chan HashField, bufferSize)
+	pager := i.pager()
 
 	go func() {
 		defer close(ch)
-
-		i.opts.Command = "HSCAN"
-		i.opts.Key = i.hash.key
-
-		scanner := radix.NewScanner(i.hash.cyclone.Raw, i.opts)
-		defer func() {
-			if err := scanner.Close(); err != nil {
-				// TODO: handle error
-			}
-		}()
-
-		var field HashField
-		toggle := true
-		hasNext := false
-
-		for {
-			if toggle {
-				hasNext = scanner.Next(&field.Key)
-			} else {
-				hasNext = scanner.Next(&field.Val)
-				ch <- field
+		scanChan(pager, &i.scanCtl, func(elems []string, abort, done <-chan struct{}) bool {
+			for n := 0; n+1 < len(elems); n += 2 {
+				select {
+				case ch <- HashField{Key: elems[n], Val: elems[n+1]}:
+				case <-abort:
+					return false
+				case <-done:
+					return false
+				}
 			}
-			if !hasNext {
-				break
-			}
-			toggle = !toggle
-		}
+			return true
+		})
 	}()
 	return ch
 }
+
+// Iter returns pull based iterator. Fields are requested page by page
+// when Next is called, no goroutine is started.
+//
+//   it := redis.Hash("big").Scan().Count(100).Iter()
+//   for it.Next() {
+//     log.Println(it.Field(), it.Value())
+//   }
+//   if err := it.Err(); err != nil { ... }
+//
+func (i *HashScanIterator) Iter() *HashIter {
+	pager := i.pager()
+	return &HashIter{pager: pager, cursor: pager.cursor}
+}
+
+// Pages returns pull based iterator which yields one HSCAN page at a time.
+// Page may be empty when no field on it matches pattern.
+func (i *HashScanIterator) Pages() *HashPages {
+	return &HashPages{pager: i.pager()}
+}
+
+// pager returns HSCAN pager with iterator options.
+func (i *HashScanIterator) pager() scanPager {
+	i.opts.Command = "HSCAN"
+	i.opts.Key = i.hash.key
+	return scanPager{cyclone: i.hash.cyclone, opts: i.opts, cursor: i.Cursor(), values: true}
+}
+
+// Next advances iterator to the next field. It returns false when iteration
+// is complete or an error occurred, check Err afterwards.
+func (it *HashIter) Next() bool {
+	for len(it.page) < 2 {
+		// all fields of the previous page were handled
+		it.cursor = it.pager.cursor
+		if it.err != nil || it.pager.done {
+			return false
+		}
+		it.page, it.err = it.pager.next()
+	}
+	it.field, it.value, it.page = it.page[0], it.page[1], it.page[2:]
+	return true
+}
+
+// Field returns current field name.
+func (it *HashIter) Field() string {
+	return it.field
+}
+
+// Value returns current field value.
+func (it *HashIter) Value() string {
+	return it.value
+}
+
+// Cursor returns cursor to resume iteration from, see From. It is advanced
+// when Next is called after the last field of a page, so the current field
+// is covered by it. Cursor "0" is returned after iteration is complete.
+func (it *HashIter) Cursor() string {
+	return it.cursor
+}
+
+// Err returns error which ended iteration.
+func (it *HashIter) Err() error {
+	return it.err
+}
+
+// Next requests the next page. It returns false when iteration
+// is complete or an error occurred, check Err afterwards.
+func (p *HashPages) Next() bool {
+	if p.err != nil || p.pager.done {
+		return false
+	}
+	elems, err := p.pager.next()
+	if err != nil {
+		p.err = err
+		p.fields = nil
+		return false
+	}
+	p.fields = make([]HashField, len(elems)/2)
+	for i := range p.fields {
+		p.fields[i] = HashField{Key: elems[2*i], Val: elems[2*i+1]}
+	}
+	return true
+}
+
+// Fields returns fields of the current page.
+func (p *HashPages) Fields() []HashField {
+	return p.fields
+}
+
+// Cursor returns cursor of the next page to resume iteration from, see From.
+// Cursor "0" is returned after iteration is complete.
+func (p *HashPages) Cursor() string {
+	return p.pager.cursor
+}
+
+// Err returns error which ended iteration.
+func (p *HashPages) Err() error {
+	return p.err
+}
+
+// set sets already encoded field/value pairs.
+func (l *Hash) set(kvpairs []interface{}) (addedFields int, err error) {
+	err = l.cyclone.do(radix.FlatCmd(
+		&addedFields,
+		"HSET",
+		l.key,
+		kvpairs...,
+	))
+	return
+}
+
+// getParsed parses value associated with field into v, see SetStruct for formats.
+func (l *Hash) getParsed(field string, v interface{}) error {
+	var value string
+	mn := radix.MaybeNil{Rcv: &value}
+	if err := nilErr(&mn, l.cyclone.do(radix.Cmd(&mn, "HGET", l.key, field))); err != nil {
+		return err
+	}
+	value, err := l.cyclone.restore(value)
+	if err != nil {
+		return err
+	}
+	if err := parseValue(reflect.ValueOf(v).Elem(), value, l.cyclone.nestedCodec()); err != nil {
+		return &FieldError{Field: field, Value: value, Err: err}
+	}
+	return nil
+}
+
+// nullStrings is an array reply which may contain nil elements, e.g. of HMGET.
+// nullStrings is a reply of bulk strings which may be nil.
+type nullStrings []*string
+
+// restore restores non-nil values with Cyclone's transformer in place.
+func (r nullStrings) restore(c *Cyclone) (err error) {
+	for _, value := range r {
+		if value == nil {
+			continue
+		}
+		if *value, err = c.restore(*value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// strings returns values with empty strings in place of nil values.
+func (r nullStrings) strings() []string {
+	values := make([]string, len(r))
+	for i, value := range r {
+		if value != nil {
+			values[i] = *value
+		}
+	}
+	return values
+}
+
+func (r *nullStrings) UnmarshalRESP(br *bufio.Reader) error {
+	var ah resp2.ArrayHeader
+	if err := ah.UnmarshalRESP(br); err != nil {
+		return err
+	}
+	if ah.N < 0 {
+		*r = nil
+		return nil
+	}
+
+	*r = make(nullStrings, ah.N)
+	var bs resp2.BulkStringBytes
+	for i := range *r {
+		if err := bs.UnmarshalRESP(br); err != nil {
+			return err
+		}
+		if bs.B != nil {
+			s := string(bs.B)
+			(*r)[i] = &s
+		}
+	}
+	return nil
+}
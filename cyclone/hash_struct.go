@@ -0,0 +1,310 @@
+package cyclone
+
+import (
+	"encoding"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+	// structFieldsCache holds []structField for every mapped struct type.
+	structFieldsCache sync.Map
+)
+
+// structField describes exported struct field mapped to hash field.
+type structField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+// SetStruct sets hash fields from exported fields of struct v (or pointer to struct).
+// Hash field names are taken from `redis:"name"` tags, untagged fields use Go field name
+// and fields tagged `redis:"-"` are skipped. Tag option omitempty skips zero values
+// and nil pointers are never set. Embedded structs are flattened.
+//
+//   type User struct {
+//     Name    string        `redis:"name"`
+//     Age     int           `redis:"age,omitempty"`
+//     Created time.Time     `redis:"created"`
+//     TTL     time.Duration `redis:"ttl"`
+//     Email   *string       `redis:"email"`
+//     Address Address       `redis:"address"`
+//   }
+//
+// Strings, []byte, numbers and bools are stored as plain values, durations as
+// time.Duration string, encoding.TextMarshaler (e.g. time.Time) as text and any other
+// value (structs, maps, slices) is encoded with codec (JSON when not set), see WithCodec.
+// https://redis.io/commands/hset
+//
+// Time complexity: O(1) for each field/value pair added, so O(N) to add N field/value pairs
+func (l *Hash) SetStruct(v interface{}) (addedFields int, err error) {
+	rv, err := structValue(v)
+	if err != nil {
+		return 0, err
+	}
+
+	kvpairs := make([]interface{}, 0)
+	for _, f := range structFields(rv.Type()) {
+		fv := rv.FieldByIndex(f.index)
+		if (fv.Kind() == reflect.Ptr && fv.IsNil()) || (f.omitEmpty && fv.IsZero()) {
+			continue
+		}
+		s, err := formatValue(fv, l.cyclone.nestedCodec())
+		if err != nil {
+			return 0, &FieldError{Field: f.name, Err: err}
+		}
+		value, err := l.cyclone.transform([]byte(s))
+		if err != nil {
+			return 0, err
+		}
+		kvpairs = append(kvpairs, f.name, value)
+	}
+	if len(kvpairs) == 0 {
+		return 0, nil
+	}
+	return l.set(kvpairs)
+}
+
+// GetStruct reads all hash fields into struct pointed by v, see SetStruct for mapping.
+// Fields missing in the hash are left untouched. ErrNil is returned when hash does not exist
+// and *FieldError when a value cannot be parsed.
+// https://redis.io/commands/hgetall
+//
+// Time complexity: O(N) where N is the size of the hash.
+func (l *Hash) GetStruct(v interface{}) error {
+	rv, err := structPtrValue(v)
+	if err != nil {
+		return err
+	}
+
+	all, err := l.GetAll()
+	if err != nil {
+		return err
+	}
+	if len(all) == 0 {
+		return ErrNil
+	}
+
+	for _, f := range structFields(rv.Type()) {
+		if s, ok := all[f.name]; ok {
+			if err := parseField(rv, f, s, l.cyclone.nestedCodec()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// MGetStruct is equal to GetStruct, but it reads only hash fields mapped by struct v.
+// ErrNil is returned when none of the fields exists.
+// https://redis.io/commands/hmget
+//
+// Time complexity: O(N) where N is the number of fields being requested.
+func (l *Hash) MGetStruct(v interface{}) error {
+	rv, err := structPtrValue(v)
+	if err != nil {
+		return err
+	}
+
+	fields := structFields(rv.Type())
+	if len(fields) == 0 {
+		return nil
+	}
+	names := make([]interface{}, len(fields))
+	for i, f := range fields {
+		names[i] = f.name
+	}
+
+	values, err := l.MLookup(names...)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for i, f := range fields {
+		if i >= len(values) || values[i] == nil {
+			continue
+		}
+		found = true
+		if err := parseField(rv, f, *values[i], l.cyclone.nestedCodec()); err != nil {
+			return err
+		}
+	}
+	if !found {
+		return ErrNil
+	}
+	return nil
+}
+
+// structValue returns struct value of v which is a struct or a pointer to struct.
+func structValue(v interface{}) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return rv, errors.New("cyclone: struct or pointer to struct expected")
+	}
+	return rv, nil
+}
+
+// structPtrValue returns settable struct value of v which is a pointer to struct.
+func structPtrValue(v interface{}) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return rv, errors.New("cyclone: non-nil pointer to struct expected")
+	}
+	return rv.Elem(), nil
+}
+
+// structFields returns cached mapped fields of struct type t.
+func structFields(t reflect.Type) []structField {
+	if fields, ok := structFieldsCache.Load(t); ok {
+		return fields.([]structField)
+	}
+	fields, _ := structFieldsCache.LoadOrStore(t, collectFields(t, nil))
+	return fields.([]structField)
+}
+
+// collectFields collects mapped fields of struct type t, index is index path of t.
+func collectFields(t reflect.Type, index []int) []structField {
+	fields := make([]structField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, hasTag := sf.Tag.Lookup("redis")
+		if tag == "-" {
+			continue
+		}
+		path := append(append([]int{}, index...), i)
+
+		if sf.Anonymous && !hasTag && sf.Type.Kind() == reflect.Struct {
+			fields = append(fields, collectFields(sf.Type, path)...)
+			continue
+		}
+		if sf.PkgPath != "" {
+			// unexported
+			continue
+		}
+
+		opts := strings.Split(tag, ",")
+		f := structField{name: opts[0], index: path}
+		if f.name == "" {
+			f.name = sf.Name
+		}
+		for _, opt := range opts[1:] {
+			if opt == "omitempty" {
+				f.omitEmpty = true
+			}
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// parseField parses s into field f of struct value v.
+func parseField(v reflect.Value, f structField, s string, codec Codec) error {
+	if err := parseValue(v.FieldByIndex(f.index), s, codec); err != nil {
+		return &FieldError{Field: f.name, Value: s, Err: err}
+	}
+	return nil
+}
+
+// formatValue formats v as hash field value, nested values are encoded with codec.
+// Invalid values and nil pointers are formatted as empty string.
+func formatValue(v reflect.Value, codec Codec) (string, error) {
+	if !v.IsValid() {
+		return "", nil
+	}
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", nil
+		}
+		return formatValue(v.Elem(), codec)
+	}
+	if v.Type() == durationType {
+		return time.Duration(v.Int()).String(), nil
+	}
+	if v.Type().Implements(textMarshalerType) {
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		if v.Bool() {
+			return "1", nil
+		}
+		return "0", nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits()), nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return string(v.Bytes()), nil
+		}
+	}
+
+	b, err := codec.Marshal(v.Interface())
+	return string(b), err
+}
+
+// parseValue parses hash field value s into settable v, nested values are decoded with codec.
+func parseValue(v reflect.Value, s string, codec Codec) error {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return parseValue(v.Elem(), s, codec)
+	}
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		v.SetInt(int64(d))
+		return err
+	}
+	if reflect.PtrTo(v.Type()).Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+		return nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		v.SetBool(b)
+		return err
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		v.SetInt(n)
+		return err
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		v.SetUint(n)
+		return err
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		v.SetFloat(n)
+		return err
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			v.SetBytes([]byte(s))
+			return nil
+		}
+	}
+
+	return codec.Unmarshal([]byte(s), v.Addr().Interface())
+}
@@ -0,0 +1,192 @@
+package cyclone
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/franela/goblin"
+)
+
+type structAddress struct {
+	City string `json:"city"`
+	Zip  string `json:"zip"`
+}
+
+type structMeta struct {
+	Version int `redis:"version"`
+}
+
+type structUser struct {
+	structMeta
+	Name     string            `redis:"name"`
+	Age      int               `redis:"age,omitempty"`
+	Score    float64           `redis:"score"`
+	Admin    bool              `redis:"admin"`
+	Created  time.Time         `redis:"created"`
+	TTL      time.Duration     `redis:"ttl"`
+	Email    *string           `redis:"email"`
+	Avatar   []byte            `redis:"avatar"`
+	Address  *structAddress    `redis:"address"`
+	Tags     map[string]string `redis:"tags,omitempty"`
+	Untagged uint8
+	Skipped  string `redis:"-"`
+	private  string
+}
+
+func TestHashStruct(t *testing.T) {
+	g := goblin.Goblin(t)
+
+	withConn(func(c *Cyclone) {
+		created := time.Date(2020, 5, 17, 10, 30, 0, 123, time.UTC)
+		email := "bob@example.com"
+
+		g.Describe(".SetStruct", func() {
+			g.It("Sets mapped fields", func() {
+				added, err := c.Hash("HashSetStruct").SetStruct(structUser{
+					structMeta: structMeta{Version: 2},
+					Name:       "bob",
+					Score:      1.5,
+					Admin:      true,
+					Created:    created,
+					TTL:        90 * time.Second,
+					Email:      &email,
+					Avatar:     []byte{0, 1},
+					Address:    &structAddress{City: "Paris", Zip: "75001"},
+					Untagged:   7,
+					Skipped:    "skipped",
+					private:    "private",
+				})
+				g.Assert(err).Eql(nil)
+				g.Assert(added).Eql(10)
+
+				all, _ := c.Hash("HashSetStruct").GetAll()
+				g.Assert(all).Eql(map[string]string{
+					"version":  "2",
+					"name":     "bob",
+					"score":    "1.5",
+					"admin":    "1",
+					"created":  "2020-05-17T10:30:00.000000123Z",
+					"ttl":      "1m30s",
+					"email":    "bob@example.com",
+					"avatar":   "\x00\x01",
+					"address":  `{"city":"Paris","zip":"75001"}`,
+					"Untagged": "7",
+				})
+			})
+
+			g.It("Rejects non struct values", func() {
+				_, err := c.Hash("HashSetStructInvalid").SetStruct("bob")
+				g.Assert(err == nil).IsFalse()
+			})
+		})
+
+		g.Describe(".GetStruct", func() {
+			g.It("Reads mapped fields", func() {
+				in := structUser{
+					structMeta: structMeta{Version: 3},
+					Name:       "alice",
+					Age:        30,
+					Created:    created,
+					TTL:        time.Hour,
+					Email:      &email,
+					Address:    &structAddress{City: "Rome"},
+					Tags:       map[string]string{"a": "b"},
+				}
+				c.Hash("HashGetStruct").SetStruct(&in)
+
+				var out structUser
+				g.Assert(c.Hash("HashGetStruct").GetStruct(&out)).Eql(nil)
+				g.Assert(out.Version).Eql(3)
+				g.Assert(out.Name).Eql("alice")
+				g.Assert(out.Age).Eql(30)
+				g.Assert(out.Created.Equal(created)).IsTrue()
+				g.Assert(out.TTL).Eql(time.Hour)
+				g.Assert(*out.Email).Eql(email)
+				g.Assert(*out.Address).Eql(structAddress{City: "Rome"})
+				g.Assert(out.Tags).Eql(map[string]string{"a": "b"})
+			})
+
+			g.It("Leaves missing fields untouched", func() {
+				c.Hash("HashGetStructPartial").Set("name", "bob")
+
+				out := structUser{Age: 5}
+				g.Assert(c.Hash("HashGetStructPartial").GetStruct(&out)).Eql(nil)
+				g.Assert(out.Name).Eql("bob")
+				g.Assert(out.Age).Eql(5)
+				g.Assert(out.Email == nil).IsTrue()
+				g.Assert(out.Address == nil).IsTrue()
+			})
+
+			g.It("Returns ErrNil for missing hash", func() {
+				var out structUser
+				g.Assert(c.Hash("HashGetStructMissing").GetStruct(&out)).Eql(ErrNil)
+			})
+
+			g.It("Returns FieldError for invalid value", func() {
+				c.Hash("HashGetStructInvalid").Set("age", "old")
+
+				var out structUser
+				err := c.Hash("HashGetStructInvalid").GetStruct(&out)
+
+				var fieldErr *FieldError
+				g.Assert(errors.As(err, &fieldErr)).IsTrue()
+				g.Assert(fieldErr.Field).Eql("age")
+				g.Assert(fieldErr.Value).Eql("old")
+				g.Assert(errors.Is(err, strconv.ErrSyntax)).IsTrue()
+			})
+
+			g.It("Requires pointer to struct", func() {
+				var out structUser
+				g.Assert(c.Hash("HashGetStructMissing").GetStruct(out) == nil).IsFalse()
+			})
+		})
+
+		g.Describe(".MGetStruct", func() {
+			g.It("Reads mapped fields only", func() {
+				c.Hash("HashMGetStruct").Set("name", "bob", "admin", "true", "other", "x")
+
+				var out structUser
+				g.Assert(c.Hash("HashMGetStruct").MGetStruct(&out)).Eql(nil)
+				g.Assert(out.Name).Eql("bob")
+				g.Assert(out.Admin).IsTrue()
+			})
+
+			g.It("Distinguishes empty and missing fields", func() {
+				c.Hash("HashMGetStructEmpty").Set("email", "")
+
+				var out structUser
+				g.Assert(c.Hash("HashMGetStructEmpty").MGetStruct(&out)).Eql(nil)
+				g.Assert(*out.Email).Eql("")
+				g.Assert(out.Address == nil).IsTrue()
+			})
+
+			g.It("Returns ErrNil when no field exists", func() {
+				c.Hash("HashMGetStructOther").Set("other", "x")
+
+				var out structUser
+				g.Assert(c.Hash("HashMGetStructOther").MGetStruct(&out)).Eql(ErrNil)
+			})
+		})
+
+		g.Describe("formatValue", func() {
+			g.It("Formats nil pointers as empty values", func() {
+				s, err := formatValue(reflect.ValueOf((*int)(nil)), JSON)
+				g.Assert(err).Eql(nil)
+				g.Assert(s).Eql("")
+
+				s, err = formatValue(reflect.Value{}, JSON)
+				g.Assert(err).Eql(nil)
+				g.Assert(s).Eql("")
+
+				h := c.Hash("HashFormatNil").WithTransformer(NewCompression(Gzip, CompressOptions{}))
+				_, err = h.Set("f", (*int)(nil))
+				g.Assert(err).Eql(nil)
+				val, _ := h.Get("f")
+				g.Assert(val).Eql("")
+			})
+		})
+	})
+}
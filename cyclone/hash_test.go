@@ -1,9 +1,12 @@
 package cyclone
 
 import (
+	"context"
+	"errors"
 	"log"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/franela/goblin"
 	"github.com/mediocregopher/radix/v3"
@@ -12,6 +15,69 @@ import (
 func TestHash(t *testing.T) {
 	g := goblin.Goblin(t)
 
+	g.Describe(".Scan cursor", func() {
+		c := scanStub("HSCAN", []string{"a", "1"}, []string{"b", "2"}, []string{"c", "3"})
+
+		g.It("Resumes Chan iteration from cursor", func() {
+			it := c.Hash("HashCursor").Scan()
+			g.Assert(it.Cursor()).Eql("0")
+
+			fields := []string{}
+			for field := range it.From("1").Chan(0) {
+				fields = append(fields, field)
+			}
+			g.Assert(fields).Eql([]string{"b", "2", "c", "3"})
+			g.Assert(it.Cursor()).Eql("0")
+			g.Assert(it.Err()).Eql(nil)
+		})
+
+		g.It("Advances Chan cursor after page is sent", func() {
+			it := c.Hash("HashCursor").Scan()
+			ch := it.ChanKV(0)
+			time.Sleep(10 * time.Millisecond)
+			g.Assert(it.Cursor()).Eql("0")
+			g.Assert(<-ch).Eql(HashField{"a", "1"})
+			it.Stop()
+
+			fields := map[string]string{"a": "1"}
+			for field := range ch {
+				fields[field.Key] = field.Val
+			}
+			if it.Cursor() != "0" {
+				for field := range c.Hash("HashCursor").Scan().From(it.Cursor()).ChanKV(0) {
+					fields[field.Key] = field.Val
+				}
+			}
+			g.Assert(fields).Eql(map[string]string{"a": "1", "b": "2", "c": "3"})
+		})
+
+		g.It("Exposes Iter cursor", func() {
+			it := c.Hash("HashCursor").Scan().Iter()
+			cursors := []string{}
+			for it.Next() {
+				cursors = append(cursors, it.Field()+it.Cursor())
+			}
+			g.Assert(cursors).Eql([]string{"a0", "b1", "c2"})
+			g.Assert(it.Cursor()).Eql("0")
+
+			it = c.Hash("HashCursor").Scan().From("2").Iter()
+			g.Assert(it.Next()).IsTrue()
+			g.Assert(it.Field()).Eql("c")
+			g.Assert(it.Next()).IsFalse()
+		})
+
+		g.It("Exposes Pages cursor", func() {
+			pages := c.Hash("HashCursor").Scan().From("1").Pages()
+			g.Assert(pages.Next()).IsTrue()
+			g.Assert(pages.Fields()).Eql([]HashField{{"b", "2"}})
+			g.Assert(pages.Cursor()).Eql("2")
+			g.Assert(pages.Next()).IsTrue()
+			g.Assert(pages.Cursor()).Eql("0")
+			g.Assert(pages.Next()).IsFalse()
+			g.Assert(pages.Err()).Eql(nil)
+		})
+	})
+
 	withConn(func(c *Cyclone) {
 		g.Describe(".Del", func() {
 			g.It("Deletes keys and returns deleted count", func() {
@@ -68,6 +134,99 @@ func TestHash(t *testing.T) {
 			})
 		})
 
+		g.Describe(".GetInt", func() {
+			g.It("Returns typed values", func() {
+				c.Hash("HashGetTyped").Set(
+					"int", "-42",
+					"float", "1.5",
+					"bool", "true",
+					"time", "2020-05-17T10:30:00Z",
+					"duration", "1m30s",
+					"bytes", "\x00\x01",
+					"json", `{"a":[1,2]}`,
+				)
+				h := c.Hash("HashGetTyped")
+
+				i, err := h.GetInt("int")
+				g.Assert(i).Eql(-42)
+				g.Assert(err).Eql(nil)
+
+				i64, _ := h.GetInt64("int")
+				g.Assert(i64).Eql(int64(-42))
+
+				f, _ := h.GetFloat("float")
+				g.Assert(f).Eql(1.5)
+
+				b, _ := h.GetBool("bool")
+				g.Assert(b).IsTrue()
+
+				tm, _ := h.GetTime("time")
+				g.Assert(tm.Equal(time.Date(2020, 5, 17, 10, 30, 0, 0, time.UTC))).IsTrue()
+
+				d, _ := h.GetDuration("duration")
+				g.Assert(d).Eql(90 * time.Second)
+
+				bytes, _ := h.GetBytes("bytes")
+				g.Assert(bytes).Eql([]byte{0, 1})
+
+				var v map[string][]int
+				g.Assert(h.GetJSON("json", &v)).Eql(nil)
+				g.Assert(v).Eql(map[string][]int{"a": {1, 2}})
+			})
+
+			g.It("Returns ErrNil for missing field", func() {
+				c.Hash("HashGetTypedMissing").Set("a", "")
+				h := c.Hash("HashGetTypedMissing")
+
+				_, err := h.GetInt("missing")
+				g.Assert(err).Eql(ErrNil)
+				_, err = h.GetTime("missing")
+				g.Assert(err).Eql(ErrNil)
+				_, err = h.GetBytes("missing")
+				g.Assert(err).Eql(ErrNil)
+				var v interface{}
+				g.Assert(h.GetJSON("missing", &v)).Eql(ErrNil)
+
+				bytes, err := h.GetBytes("a")
+				g.Assert(len(bytes)).Eql(0)
+				g.Assert(err).Eql(nil)
+			})
+
+			g.It("Returns FieldError for invalid value", func() {
+				c.Hash("HashGetTypedInvalid").Set("a", "x")
+				h := c.Hash("HashGetTypedInvalid")
+
+				var fieldErr *FieldError
+				_, err := h.GetInt("a")
+				g.Assert(errors.As(err, &fieldErr)).IsTrue()
+				g.Assert(fieldErr.Field).Eql("a")
+				g.Assert(fieldErr.Value).Eql("x")
+				g.Assert(errors.Is(err, strconv.ErrSyntax)).IsTrue()
+
+				_, err = h.GetFloat("a")
+				g.Assert(errors.As(err, &fieldErr)).IsTrue()
+				_, err = h.GetBool("a")
+				g.Assert(errors.As(err, &fieldErr)).IsTrue()
+				_, err = h.GetDuration("a")
+				g.Assert(errors.As(err, &fieldErr)).IsTrue()
+				_, err = h.GetTime("a")
+				g.Assert(errors.As(err, &fieldErr)).IsTrue()
+				var v interface{}
+				g.Assert(errors.As(h.GetJSON("a", &v), &fieldErr)).IsTrue()
+			})
+		})
+
+		g.Describe(".GetInto", func() {
+			g.It("Reads value into value without codec", func() {
+				c.Hash("HashGetInto").Set("n", 5)
+
+				var n int
+				g.Assert(c.Hash("HashGetInto").GetInto("n", &n)).Eql(nil)
+				g.Assert(n).Eql(5)
+				g.Assert(c.Hash("HashGetInto").GetInto("missing", &n)).Eql(ErrNil)
+			})
+		})
+
 		g.Describe(".Incr", func() {
 			g.It("Increments a floating field and returns incrmented value", func() {
 				c.Raw.Do(radix.Cmd(nil, "HSET", "HashIncr", "a", "1", "b", "2"))
@@ -112,6 +271,28 @@ func TestHash(t *testing.T) {
 			})
 		})
 
+		g.Describe(".Lookup", func() {
+			g.It("Distinguishes empty and missing field", func() {
+				c.Raw.Do(radix.Cmd(nil, "HSET", "HashLookup", "a", "1", "empty", ""))
+
+				val, ok, err := c.Hash("HashLookup").Lookup("a")
+				g.Assert(val).Eql("1")
+				g.Assert(ok).IsTrue()
+				g.Assert(err).Eql(nil)
+
+				val, ok, _ = c.Hash("HashLookup").Lookup("empty")
+				g.Assert(val).Eql("")
+				g.Assert(ok).IsTrue()
+
+				_, ok, err = c.Hash("HashLookup").Lookup("missing")
+				g.Assert(ok).IsFalse()
+				g.Assert(err).Eql(nil)
+
+				_, ok, _ = c.Hash("HashLookupMissing").Lookup("a")
+				g.Assert(ok).IsFalse()
+			})
+		})
+
 		g.Describe(".MGet", func() {
 			g.It("Returns selected key values", func() {
 				c.Raw.Do(radix.Cmd(nil, "HSET", "HashMGet", "a", "1", "b", "2", "c", "3"))
@@ -122,6 +303,22 @@ func TestHash(t *testing.T) {
 			})
 		})
 
+		g.Describe(".MLookup", func() {
+			g.It("Returns nil for missing fields", func() {
+				c.Raw.Do(radix.Cmd(nil, "HSET", "HashMLookup", "a", "1", "empty", ""))
+
+				val, err := c.Hash("HashMLookup").MLookup("a", "missing", "empty")
+				g.Assert(err).Eql(nil)
+				g.Assert(len(val)).Eql(3)
+				g.Assert(*val[0]).Eql("1")
+				g.Assert(val[1] == nil).IsTrue()
+				g.Assert(*val[2]).Eql("")
+
+				val, _ = c.Hash("HashMLookupMissing").MLookup("a")
+				g.Assert(val).Eql([]*string{nil})
+			})
+		})
+
 		g.Describe(".Scan", func() {
 			g.It("Chan iteration", func() {
 				for i := 0; i < 100; i++ {
@@ -180,6 +377,104 @@ func TestHash(t *testing.T) {
 				g.Assert(result["28"]).Eql("28")
 				g.Assert(result["29"]).Eql("29")
 			})
+
+			g.It("Reports nil error after complete iteration", func() {
+				c.Hash("HashScanErr").Set("a", "1")
+
+				it := c.Hash("HashScanErr").Scan()
+				for range it.ChanKV(0) {
+				}
+				g.Assert(it.Err()).Eql(nil)
+			})
+
+			g.It("Reports error which ended iteration", func() {
+				c.String("HashScanWrongType").Set("a")
+
+				it := c.Hash("HashScanWrongType").Scan()
+				count := 0
+				for range it.ChanKV(0) {
+					count++
+				}
+				g.Assert(count).Eql(0)
+				g.Assert(it.Err()).Eql(ErrWrongType)
+			})
+
+			g.It("Stops iteration", func() {
+				for i := 0; i < 100; i++ {
+					c.Hash("HashScanStop").Set(strconv.Itoa(i), strconv.Itoa(i))
+				}
+
+				it := c.Hash("HashScanStop").Scan().Count(10)
+				ch := it.ChanKV(0)
+				<-ch
+				it.Stop()
+				it.Stop()
+
+				count := 0
+				for range ch {
+					count++
+				}
+				g.Assert(count < 99).IsTrue()
+				g.Assert(it.Err()).Eql(nil)
+			})
+
+			g.It("Reports context error", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				c.Hash("HashScanCtx").Set("a", "1")
+
+				it := c.WithContext(ctx).Hash("HashScanCtx").Scan()
+				ch := it.Chan(0)
+				cancel()
+				for range ch {
+				}
+				g.Assert(it.Err()).Eql(context.Canceled)
+			})
+
+			g.It("Iter iteration", func() {
+				for i := 0; i < 100; i++ {
+					c.Hash("HashScanIter").Set(strconv.Itoa(i), strconv.Itoa(i))
+				}
+
+				it := c.Hash("HashScanIter").Scan().Count(10).Iter()
+				result := make(map[string]string, 0)
+				for it.Next() {
+					result[it.Field()] = it.Value()
+				}
+
+				g.Assert(it.Err()).Eql(nil)
+				g.Assert(len(result)).Eql(100)
+				for i := 0; i < 100; i++ {
+					g.Assert(result[strconv.Itoa(i)]).Eql(strconv.Itoa(i))
+				}
+			})
+
+			g.It("Iter reports error", func() {
+				c.String("HashScanIterWrongType").Set("a")
+
+				it := c.Hash("HashScanIterWrongType").Scan().Iter()
+				g.Assert(it.Next()).IsFalse()
+				g.Assert(it.Err()).Eql(ErrWrongType)
+			})
+
+			g.It("Pages iteration", func() {
+				for i := 0; i < 100; i++ {
+					c.Hash("HashScanPages").Set(strconv.Itoa(i), strconv.Itoa(i))
+				}
+
+				pages := c.Hash("HashScanPages").Scan().Count(30).Pages()
+				result := make(map[string]string, 0)
+				count := 0
+				for pages.Next() {
+					count++
+					for _, kv := range pages.Fields() {
+						result[kv.Key] = kv.Val
+					}
+				}
+
+				g.Assert(pages.Err()).Eql(nil)
+				g.Assert(count >= 1).IsTrue()
+				g.Assert(len(result)).Eql(100)
+			})
 		})
 
 		g.Describe(".Set", func() {
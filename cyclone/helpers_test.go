@@ -1,8 +1,10 @@
 package cyclone
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strconv"
 
 	"github.com/mediocregopher/radix/v3"
 )
@@ -18,3 +20,23 @@ func withConn(with func(*Cyclone)) {
 
 	raw.Do(radix.Cmd(nil, "FLUSHALL"))
 }
+
+// scanStub replies to SCAN family command with pages, page i is returned
+// for cursor i and points to the next one.
+func scanStub(command string, pages ...[]string) *Cyclone {
+	return New(radix.Stub("tcp", "127.0.0.1:6379", func(args []string) interface{} {
+		cursor := args[1]
+		if command != "SCAN" {
+			cursor = args[2]
+		}
+		i, _ := strconv.Atoi(cursor)
+		if args[0] != command || i >= len(pages) {
+			return errors.New("ERR invalid cursor")
+		}
+		next := strconv.Itoa(i + 1)
+		if i+1 == len(pages) {
+			next = "0"
+		}
+		return []interface{}{next, pages[i]}
+	}))
+}
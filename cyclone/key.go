@@ -0,0 +1,247 @@
+package cyclone
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/mediocregopher/radix/v3"
+)
+
+// Key wraps redis generic key operations. It is embedded in all type
+// wrappers, so e.g. c.Hash("stats").Expire(time.Hour) works as well.
+type Key struct {
+	cyclone *Cyclone
+	key     string
+}
+
+// Copy copies the value stored at key to dst key. Returns false when
+// dst already exists and replace is false.
+// https://redis.io/commands/copy
+//
+// Time complexity: O(N) worst case for collections, where N is the number
+//                  of nested items. O(1) for string values.
+func (k *Key) Copy(dst string, replace bool) (bool, error) {
+	args := []string{k.key, dst}
+	if replace {
+		args = append(args, "REPLACE")
+	}
+	var copied int
+	err := k.cyclone.do(radix.Cmd(&copied, "COPY", args...))
+	return copied == 1, err
+}
+
+// Del removes the key. Returns false when key did not exist.
+// https://redis.io/commands/del
+//
+// Time complexity: O(M) where M is the number of elements in the key
+//                  (e.g. list, set, hash), O(1) for strings.
+func (k *Key) Del() (bool, error) {
+	var deleted int
+	err := k.cyclone.do(radix.Cmd(&deleted, "DEL", k.key))
+	return deleted == 1, err
+}
+
+// Dump returns serialized value stored at key, which can be used by Restore.
+// ErrNil is returned when key does not exist.
+// https://redis.io/commands/dump
+//
+// Time complexity: O(1) to access the key and additional O(N*M) to serialize it,
+//                  where N is the number of Redis objects composing the value
+//                  and M their average size.
+func (k *Key) Dump() (serialized string, err error) {
+	mn := radix.MaybeNil{Rcv: &serialized}
+	err = k.cyclone.do(radix.Cmd(&mn, "DUMP", k.key))
+	err = nilErr(&mn, err)
+	return
+}
+
+// Encoding returns the internal encoding used to store the value at key
+// (OBJECT ENCODING). ErrNil is returned when key does not exist.
+// https://redis.io/commands/object-encoding
+//
+// Time complexity: O(1)
+func (k *Key) Encoding() (encoding string, err error) {
+	mn := radix.MaybeNil{Rcv: &encoding}
+	err = k.cyclone.do(radix.Cmd(&mn, "OBJECT", "ENCODING", k.key))
+	err = nilErr(&mn, err)
+	return
+}
+
+// Exists returns if key exists.
+// https://redis.io/commands/exists
+//
+// Time complexity: O(1)
+func (k *Key) Exists() (bool, error) {
+	var exists int
+	err := k.cyclone.do(radix.Cmd(&exists, "EXISTS", k.key))
+	return exists == 1, err
+}
+
+// Expire sets a timeout on key in seconds, after which the key will be deleted.
+// Positive ttl shorter than a second is rounded up to a second, use PExpire for
+// millisecond precision. Returns false when key does not exist.
+// https://redis.io/commands/expire
+//
+// Time complexity: O(1)
+func (k *Key) Expire(ttl time.Duration) (bool, error) {
+	return k.expire("EXPIRE", formatSec(ttl))
+}
+
+// ExpireAt sets key to be deleted at the given time (with seconds precision).
+// Returns false when key does not exist.
+// https://redis.io/commands/expireat
+//
+// Time complexity: O(1)
+func (k *Key) ExpireAt(at time.Time) (bool, error) {
+	return k.expire("EXPIREAT", strconv.FormatInt(at.Unix(), 10))
+}
+
+// IdleTime returns time since the key was last accessed (OBJECT IDLETIME).
+// ErrNil is returned when key does not exist.
+// https://redis.io/commands/object-idletime
+//
+// Time complexity: O(1)
+func (k *Key) IdleTime() (time.Duration, error) {
+	var seconds int64
+	mn := radix.MaybeNil{Rcv: &seconds}
+	err := k.cyclone.do(radix.Cmd(&mn, "OBJECT", "IDLETIME", k.key))
+	return time.Duration(seconds) * time.Second, nilErr(&mn, err)
+}
+
+// Name returns the key.
+func (k *Key) Name() string {
+	return k.key
+}
+
+// PExpire sets a timeout on key in milliseconds, after which the key will be deleted.
+// Positive ttl shorter than a millisecond is rounded up to a millisecond.
+// Returns false when key does not exist.
+// https://redis.io/commands/pexpire
+//
+// Time complexity: O(1)
+func (k *Key) PExpire(ttl time.Duration) (bool, error) {
+	return k.expire("PEXPIRE", formatMs(ttl))
+}
+
+// PTTL is equal to TTL, but with milliseconds precision.
+// https://redis.io/commands/pttl
+//
+// Time complexity: O(1)
+func (k *Key) PTTL() (time.Duration, error) {
+	return k.ttl("PTTL", time.Millisecond)
+}
+
+// Persist removes the existing timeout on key. Returns false when key
+// does not exist or does not have an associated timeout.
+// https://redis.io/commands/persist
+//
+// Time complexity: O(1)
+func (k *Key) Persist() (bool, error) {
+	var persisted int
+	err := k.cyclone.do(radix.Cmd(&persisted, "PERSIST", k.key))
+	return persisted == 1, err
+}
+
+// Rename renames key to newKey, overwriting newKey when it exists.
+// The wrapper refers to newKey afterwards. ErrNoSuchKey is returned
+// when key does not exist.
+// https://redis.io/commands/rename
+//
+// Time complexity: O(1)
+func (k *Key) Rename(newKey string) error {
+	err := k.cyclone.do(radix.Cmd(nil, "RENAME", k.key, newKey))
+	if err == nil {
+		k.key = newKey
+	}
+	return err
+}
+
+// RenameNX renames key to newKey if newKey does not yet exist.
+// The wrapper refers to newKey afterwards when renamed. ErrNoSuchKey
+// is returned when key does not exist.
+// https://redis.io/commands/renamenx
+//
+// Time complexity: O(1)
+func (k *Key) RenameNX(newKey string) (bool, error) {
+	var renamed int
+	err := k.cyclone.do(radix.Cmd(&renamed, "RENAMENX", k.key, newKey))
+	if renamed == 1 {
+		k.key = newKey
+	}
+	return renamed == 1, err
+}
+
+// Restore creates key from value serialized by Dump. Zero ttl means no expiration.
+// Error is returned when key exists and replace is false.
+// https://redis.io/commands/restore
+//
+// Time complexity: O(1) to create the new key and additional O(N*M) to reconstruct
+//                  the serialized value, where N is the number of Redis objects
+//                  composing the value and M their average size.
+func (k *Key) Restore(ttl time.Duration, serialized string, replace bool) error {
+	args := []string{k.key, formatMs(ttl), serialized}
+	if replace {
+		args = append(args, "REPLACE")
+	}
+	return k.cyclone.do(radix.Cmd(nil, "RESTORE", args...))
+}
+
+// TTL returns the remaining time to live of key. It is negative when key exists
+// but has no associated expire. ErrNil is returned when key does not exist.
+// https://redis.io/commands/ttl
+//
+// Time complexity: O(1)
+func (k *Key) TTL() (time.Duration, error) {
+	return k.ttl("TTL", time.Second)
+}
+
+// Touch alters the last access time of key. Returns false when key does not exist.
+// https://redis.io/commands/touch
+//
+// Time complexity: O(1)
+func (k *Key) Touch() (bool, error) {
+	var touched int
+	err := k.cyclone.do(radix.Cmd(&touched, "TOUCH", k.key))
+	return touched == 1, err
+}
+
+// Type returns the type of value stored at key: string, list, set, zset,
+// hash or stream. "none" is returned when key does not exist.
+// https://redis.io/commands/type
+//
+// Time complexity: O(1)
+func (k *Key) Type() (typ string, err error) {
+	err = k.cyclone.do(radix.Cmd(&typ, "TYPE", k.key))
+	return
+}
+
+// Unlink is equal to Del, but reclaims memory in a different thread,
+// so it is not blocking.
+// https://redis.io/commands/unlink
+//
+// Time complexity: O(1) for each key removed regardless of its size.
+func (k *Key) Unlink() (bool, error) {
+	var unlinked int
+	err := k.cyclone.do(radix.Cmd(&unlinked, "UNLINK", k.key))
+	return unlinked == 1, err
+}
+
+func (k *Key) expire(cmd, arg string) (bool, error) {
+	var set int
+	err := k.cyclone.do(radix.Cmd(&set, cmd, k.key, arg))
+	return set == 1, err
+}
+
+func (k *Key) ttl(cmd string, unit time.Duration) (time.Duration, error) {
+	var ttl int64
+	if err := k.cyclone.do(radix.Cmd(&ttl, cmd, k.key)); err != nil {
+		return 0, err
+	}
+	switch ttl {
+	case -2:
+		return 0, ErrNil
+	case -1:
+		return -1, nil
+	}
+	return time.Duration(ttl) * unit, nil
+}
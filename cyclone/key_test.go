@@ -0,0 +1,229 @@
+package cyclone
+
+import (
+	"testing"
+	"time"
+
+	"github.com/franela/goblin"
+	"github.com/mediocregopher/radix/v3"
+)
+
+func TestKey(t *testing.T) {
+	g := goblin.Goblin(t)
+
+	g.Describe(".Encoding", func() {
+		g.It("Returns object encoding", func() {
+			stub := radix.Stub("tcp", "127.0.0.1:6379", func(args []string) interface{} {
+				if len(args) == 3 && args[0] == "OBJECT" && args[1] == "ENCODING" && args[2] == "KeyEncoding" {
+					return "listpack"
+				}
+				return nil
+			})
+			c := New(stub)
+			defer c.Close()
+
+			encoding, err := c.Key("KeyEncoding").Encoding()
+			g.Assert(encoding).Eql("listpack")
+			g.Assert(err).Eql(nil)
+
+			_, err = c.Key("KeyEncodingMissing").Encoding()
+			g.Assert(err).Eql(ErrNil)
+		})
+	})
+
+	withConn(func(c *Cyclone) {
+		g.Describe(".Copy", func() {
+			g.It("Copies value to another key", func() {
+				c.String("KeyCopy").Set("a")
+				c.String("KeyCopyDst").Set("b")
+
+				copied, _ := c.Key("KeyCopy").Copy("KeyCopyDst", false)
+				g.Assert(copied).IsFalse()
+
+				copied, _ = c.Key("KeyCopy").Copy("KeyCopyDst", true)
+				g.Assert(copied).IsTrue()
+
+				val, _ := c.String("KeyCopyDst").Get()
+				g.Assert(val).Eql("a")
+			})
+		})
+
+		g.Describe(".Del", func() {
+			g.It("Deletes and unlinks key", func() {
+				c.String("KeyDel").Set("a")
+
+				deleted, _ := c.Key("KeyDel").Del()
+				g.Assert(deleted).IsTrue()
+				deleted, _ = c.Key("KeyDel").Del()
+				g.Assert(deleted).IsFalse()
+
+				c.String("KeyUnlink").Set("a")
+				unlinked, _ := c.Key("KeyUnlink").Unlink()
+				g.Assert(unlinked).IsTrue()
+			})
+
+			g.It("Is reachable from wrappers", func() {
+				c.Hash("KeyDelHash").Set("a", "1")
+
+				deleted, _ := c.Hash("KeyDelHash").Key.Del()
+				g.Assert(deleted).IsTrue()
+
+				exists, _ := c.Hash("KeyDelHash").Key.Exists()
+				g.Assert(exists).IsFalse()
+			})
+		})
+
+		g.Describe(".Dump", func() {
+			g.It("Dumps and restores value", func() {
+				c.String("KeyDump").Set("a")
+
+				serialized, err := c.Key("KeyDump").Dump()
+				g.Assert(err).Eql(nil)
+
+				g.Assert(c.Key("KeyRestore").Restore(0, serialized, false)).Eql(nil)
+				g.Assert(c.Key("KeyRestore").Restore(0, serialized, false) == nil).IsFalse()
+				g.Assert(c.Key("KeyRestore").Restore(time.Minute, serialized, true)).Eql(nil)
+
+				val, _ := c.String("KeyRestore").Get()
+				g.Assert(val).Eql("a")
+
+				ttl, _ := c.Key("KeyRestore").TTL()
+				g.Assert(ttl > 0).IsTrue()
+
+				_, err = c.Key("KeyDumpMissing").Dump()
+				g.Assert(err).Eql(ErrNil)
+			})
+		})
+
+		g.Describe(".Exists", func() {
+			g.It("Checks existence", func() {
+				c.String("KeyExists").Set("a")
+
+				exists, _ := c.Key("KeyExists").Exists()
+				g.Assert(exists).IsTrue()
+				exists, _ = c.Key("KeyExistsMissing").Exists()
+				g.Assert(exists).IsFalse()
+			})
+		})
+
+		g.Describe(".Expire", func() {
+			g.It("Sets and removes expiration", func() {
+				hash := c.Hash("KeyExpire")
+				hash.Set("a", "1")
+
+				ttl, _ := hash.TTL()
+				g.Assert(ttl).Eql(time.Duration(-1))
+
+				set, _ := hash.Expire(time.Hour)
+				g.Assert(set).IsTrue()
+				ttl, _ = hash.TTL()
+				g.Assert(ttl > 59*time.Minute && ttl <= time.Hour).IsTrue()
+
+				set, _ = hash.PExpire(1500 * time.Millisecond)
+				g.Assert(set).IsTrue()
+				ttl, _ = hash.PTTL()
+				g.Assert(ttl > time.Second && ttl <= 1500*time.Millisecond).IsTrue()
+
+				persisted, _ := hash.Persist()
+				g.Assert(persisted).IsTrue()
+				persisted, _ = hash.Persist()
+				g.Assert(persisted).IsFalse()
+			})
+
+			g.It("Rounds sub-second expiration up", func() {
+				c.String("KeyExpireShort").Set("a")
+
+				set, _ := c.Key("KeyExpireShort").Expire(500 * time.Millisecond)
+				g.Assert(set).IsTrue()
+				ttl, err := c.Key("KeyExpireShort").TTL()
+				g.Assert(err).Eql(nil)
+				g.Assert(ttl).Eql(time.Second)
+
+				c.String("KeyExpireShort").SetWith("b").EX(time.Millisecond).Do()
+				ttl, _ = c.Key("KeyExpireShort").TTL()
+				g.Assert(ttl).Eql(time.Second)
+
+				set, _ = c.Key("KeyExpireShort").PExpire(500 * time.Microsecond)
+				g.Assert(set).IsTrue()
+				ttl, err = c.Key("KeyExpireShort").PTTL()
+				g.Assert(err).Eql(nil)
+				g.Assert(ttl).Eql(time.Millisecond)
+			})
+
+			g.It("Sets expiration at time", func() {
+				c.String("KeyExpireAt").Set("a")
+
+				set, _ := c.Key("KeyExpireAt").ExpireAt(time.Now().Add(time.Hour))
+				g.Assert(set).IsTrue()
+				ttl, _ := c.Key("KeyExpireAt").TTL()
+				g.Assert(ttl > 59*time.Minute).IsTrue()
+			})
+
+			g.It("Returns false or ErrNil for missing key", func() {
+				set, _ := c.Key("KeyExpireMissing").Expire(time.Hour)
+				g.Assert(set).IsFalse()
+
+				_, err := c.Key("KeyExpireMissing").TTL()
+				g.Assert(err).Eql(ErrNil)
+				_, err = c.Key("KeyExpireMissing").PTTL()
+				g.Assert(err).Eql(ErrNil)
+			})
+		})
+
+		g.Describe(".IdleTime", func() {
+			g.It("Returns idle time", func() {
+				c.String("KeyIdleTime").Set("a")
+
+				touched, _ := c.Key("KeyIdleTime").Touch()
+				g.Assert(touched).IsTrue()
+
+				idle, err := c.Key("KeyIdleTime").IdleTime()
+				g.Assert(err).Eql(nil)
+				g.Assert(idle < time.Minute).IsTrue()
+
+				_, err = c.Key("KeyIdleTimeMissing").IdleTime()
+				g.Assert(err).Eql(ErrNil)
+			})
+		})
+
+		g.Describe(".Rename", func() {
+			g.It("Renames key and follows new name", func() {
+				list := c.List("KeyRename")
+				list.Push("a")
+
+				g.Assert(list.Rename("KeyRenamed")).Eql(nil)
+				g.Assert(list.Name()).Eql("KeyRenamed")
+
+				length, _ := list.Len()
+				g.Assert(length).Eql(1)
+
+				g.Assert(c.Key("KeyRenameMissing").Rename("KeyRenameDst")).Eql(ErrNoSuchKey)
+			})
+
+			g.It("Renames only to missing key with RenameNX", func() {
+				c.String("KeyRenameNX").Set("a")
+				c.String("KeyRenameNXDst").Set("b")
+
+				key := c.Key("KeyRenameNX")
+				renamed, _ := key.RenameNX("KeyRenameNXDst")
+				g.Assert(renamed).IsFalse()
+				g.Assert(key.Name()).Eql("KeyRenameNX")
+
+				renamed, _ = key.RenameNX("KeyRenameNXOther")
+				g.Assert(renamed).IsTrue()
+				g.Assert(key.Name()).Eql("KeyRenameNXOther")
+			})
+		})
+
+		g.Describe(".Type", func() {
+			g.It("Returns type of value", func() {
+				c.ZSet("KeyTypeZSet").Add(ZMember{"a", 1})
+
+				typ, _ := c.Key("KeyTypeZSet").Type()
+				g.Assert(typ).Eql("zset")
+				typ, _ = c.Key("KeyTypeMissing").Type()
+				g.Assert(typ).Eql("none")
+			})
+		})
+	})
+}
@@ -2,72 +2,247 @@ package cyclone
 
 import (
 	"strconv"
+	"time"
 
 	"github.com/mediocregopher/radix/v3"
 )
 
+// List wraps redis list operations.
 type List struct {
-	cyclone *Cyclone
-	key     string
+	Key
 }
 
-//TODO: BLPOP
-//TODO: BRPOP
-//TODO: BRPOPLPUSH
+// ListPos is a LPOS query builder.
+type ListPos struct {
+	list *List
+	elem string
+	args []string
+}
+
+// Position is a place of insertion relative to a pivot element used by LINSERT.
+type Position string
+
+const (
+	// Before inserts element before the pivot.
+	Before Position = "BEFORE"
+	// After inserts element after the pivot.
+	After Position = "AFTER"
+)
+
+// BMove (BLMOVE) is the blocking variant of LMOVE. When source is empty, Redis will
+// block the connection until another client pushes to it or until timeout
+// is reached. A timeout of zero can be used to block indefinitely.
+// ErrNil is returned when timeout is reached.
+// https://redis.io/commands/blmove
+//
+// Time complexity: O(1)
+func (l *List) BMove(dst *List, from, to Direction, timeout time.Duration) (elem string, err error) {
+	mn := radix.MaybeNil{Rcv: &elem}
+	err = l.cyclone.doBlocking(radix.Cmd(
+		&mn,
+		"BLMOVE",
+		l.key,
+		dst.key,
+		string(from),
+		string(to),
+		formatTimeout(timeout),
+	))
+	if err = nilErr(&mn, err); err != nil {
+		return
+	}
+	return l.cyclone.restore(elem)
+}
+
+// BPop (BLPOP) is a blocking list pop primitive. It is the blocking version of Pop
+// because it blocks the connection when there are no elements to pop from any
+// of the given lists. An element is popped from the head of the first list
+// that is non-empty, with the given keys being checked in the order that they
+// are given. Key of the list the element was popped from is returned.
+// A timeout of zero can be used to block indefinitely.
+// ErrNil is returned when timeout is reached.
+// https://redis.io/commands/blpop
+//
+// Time complexity: O(1)
+func (l *List) BPop(timeout time.Duration, others ...*List) (key, elem string, err error) {
+	return l.bpop("BLPOP", timeout, others)
+}
+
+// BRPop (BRPOP) is a blocking list pop primitive. It is the blocking version of RPop
+// because it blocks the connection when there are no elements to pop from any
+// of the given lists. An element is popped from the tail of the first list
+// that is non-empty, with the given keys being checked in the order that they
+// are given. Key of the list the element was popped from is returned.
+// A timeout of zero can be used to block indefinitely.
+// ErrNil is returned when timeout is reached.
+// https://redis.io/commands/brpop
+//
+// Time complexity: O(1)
+func (l *List) BRPop(timeout time.Duration, others ...*List) (key, elem string, err error) {
+	return l.bpop("BRPOP", timeout, others)
+}
+
+// BRPopLPush (BRPOPLPUSH) is the blocking variant of RPOPLPUSH. When source is empty,
+// Redis will block the connection until another client pushes to it or until
+// timeout is reached. A timeout of zero can be used to block indefinitely.
+// ErrNil is returned when timeout is reached.
+// https://redis.io/commands/brpoplpush
+//
+// Time complexity: O(1)
+func (l *List) BRPopLPush(dst *List, timeout time.Duration) (elem string, err error) {
+	mn := radix.MaybeNil{Rcv: &elem}
+	err = l.cyclone.doBlocking(radix.Cmd(
+		&mn,
+		"BRPOPLPUSH",
+		l.key,
+		dst.key,
+		formatTimeout(timeout),
+	))
+	if err = nilErr(&mn, err); err != nil {
+		return
+	}
+	return l.cyclone.restore(elem)
+}
 
 // Index (LINDEX) Returns the element at index index in the list stored at key.
 // The index is zero-based, so 0 means the first element, 1 the second element
 // and so on. Negative indices can be used to designate elements starting at the
 // tail of the list. Here, -1 means the last element, -2 means the penultimate
-// and so forth.
+// and so forth. ErrNil is returned when index is out of range.
 // https://redis.io/commands/lindex
 //
 // Time complexity: O(N) where N is the number of elements to traverse to get to the
 //                  element at index. This makes asking for the first or the last
 //                  element of the list O(1).
-func (l *List) Index(index int) (elem string) {
-	l.cyclone.Raw.Do(radix.Cmd(&elem, "LINDEX", l.key, strconv.Itoa(index)))
-	return
+func (l *List) Index(index int) (elem string, err error) {
+	mn := radix.MaybeNil{Rcv: &elem}
+	err = l.cyclone.do(radix.Cmd(&mn, "LINDEX", l.key, strconv.Itoa(index)))
+	if err = nilErr(&mn, err); err != nil {
+		return
+	}
+	return l.cyclone.restore(elem)
 }
 
-//TODO: LINSERT
+// Insert (LINSERT) Inserts element in the list stored at key either before or after
+// the reference value pivot. When key does not exist, it is considered an empty
+// list and no operation is performed. Returns the length of the list after
+// the insert operation, or -1 when the value pivot was not found. Pivot is
+// encoded the same way as elements, so it never matches values encrypted
+// with a random nonce.
+// https://redis.io/commands/linsert
+//
+// Time complexity: O(N) where N is the number of elements to traverse before
+//                  seeing the value pivot. This means that inserting somewhere on
+//                  the left end on the list (head) can be considered O(1) and
+//                  inserting somewhere on the right end (tail) is O(N).
+func (l *List) Insert(where Position, pivot, elem string) (lenAfterInsert int, err error) {
+	if pivot, err = l.cyclone.encodeValue(pivot); err != nil {
+		return 0, err
+	}
+	if elem, err = l.cyclone.encodeValue(elem); err != nil {
+		return 0, err
+	}
+	err = l.cyclone.do(radix.Cmd(
+		&lenAfterInsert,
+		"LINSERT",
+		l.key,
+		string(where),
+		pivot,
+		elem,
+	))
+	return
+}
 
 // Len returns the length of the list stored at key. If key does not exist,
 // it is interpreted as an empty list and 0 is returned.
 // https://redis.io/commands/llen
 //
 // Time complexity: O(1)
-func (l *List) Len() (lenOfList int) {
-	l.cyclone.Raw.Do(radix.Cmd(&lenOfList, "LLEN", l.key))
+func (l *List) Len() (lenOfList int, err error) {
+	err = l.cyclone.do(radix.Cmd(&lenOfList, "LLEN", l.key))
 	return
 }
 
+// Move (LMOVE) Atomically returns and removes the first/last element (head/tail
+// depending on the from argument) of the list stored at source, and pushes
+// the element at the first/last element (head/tail depending on the to argument)
+// of the list stored at destination. ErrNil is returned when source is empty.
+// https://redis.io/commands/lmove
+//
+// Time complexity: O(1)
+func (l *List) Move(dst *List, from, to Direction) (elem string, err error) {
+	mn := radix.MaybeNil{Rcv: &elem}
+	err = l.cyclone.do(radix.Cmd(
+		&mn,
+		"LMOVE",
+		l.key,
+		dst.key,
+		string(from),
+		string(to),
+	))
+	if err = nilErr(&mn, err); err != nil {
+		return
+	}
+	return l.cyclone.restore(elem)
+}
+
 // Pop (LPOP) Removes and returns the first element of the list stored at key.
+// ErrNil is returned when the list is empty.
 // https://redis.io/commands/lpop
 //
 // Time complexity: O(1)
-func (l *List) Pop() (elem string) {
-	l.cyclone.Raw.Do(radix.Cmd(&elem, "LPOP", l.key))
+func (l *List) Pop() (elem string, err error) {
+	mn := radix.MaybeNil{Rcv: &elem}
+	err = l.cyclone.do(radix.Cmd(&mn, "LPOP", l.key))
+	if err = nilErr(&mn, err); err != nil {
+		return
+	}
+	return l.cyclone.restore(elem)
+}
+
+// PopInto is equal to Pop, but it decodes element into v with codec
+// (or radix when codec is not set), see WithCodec.
+// https://redis.io/commands/lpop
+//
+// Time complexity: O(1)
+func (l *List) PopInto(v interface{}) error {
+	return l.cyclone.doInto(v, "LPOP", l.key)
+}
+
+// PopN (LPOP with count) Removes and returns up to count elements from the head
+// of the list stored at key. ErrNil is returned when the list is empty.
+// https://redis.io/commands/lpop
+//
+// Time complexity: O(N) where N is the number of elements returned
+func (l *List) PopN(count int) (elems []string, err error) {
+	mn := radix.MaybeNil{Rcv: &elems}
+	err = l.cyclone.do(radix.Cmd(&mn, "LPOP", l.key, strconv.Itoa(count)))
+	if err = nilErr(&mn, err); err == nil {
+		err = l.cyclone.restoreAll(elems)
+	}
 	return
 }
 
-//TODO: LPOS
+// Pos (LPOS) Returns query builder for the index of matching elements inside the list.
+// https://redis.io/commands/lpos
+//
+// Time complexity: O(N) where N is the number of elements in the list, for the
+//                  average case. When searching for elements near the head or
+//                  the tail of the list, or when the MAXLEN option is provided,
+//                  the command may run in constant time.
+func (l *List) Pos(elem string) *ListPos {
+	return &ListPos{list: l, elem: elem}
+}
 
 // Push (LPUSH) Inserts all the specified values at the head of the list stored at key.
 // If key does not exist, it is created as empty list before performing the push
 // operations. When key holds a value that is not a list, an error is returned.
+// Values are encoded with codec when set, see WithCodec.
 // https://redis.io/commands/lpush
 //
 // Time complexity: O(1) for each element added, so O(N) to add N
 //                  elements when the command is called with multiple arguments.
-func (l *List) Push(elems ...interface{}) (lenAfterPush int) {
-	l.cyclone.Raw.Do(radix.FlatCmd(
-		&lenAfterPush,
-		"LPUSH",
-		l.key,
-		elems...,
-	))
-	return
+func (l *List) Push(elems ...interface{}) (lenAfterPush int, err error) {
+	return l.push("LPUSH", elems)
 }
 
 // PushX (LPUSHX) Inserts specified values at the head of the list stored at key,
@@ -77,14 +252,8 @@ func (l *List) Push(elems ...interface{}) (lenAfterPush int) {
 //
 // Time complexity: O(1) for each element added, so O(N) to add N elements
 //                  when the command is called with multiple arguments.
-func (l *List) PushX(elems ...interface{}) (lenAfterPush int) {
-	l.cyclone.Raw.Do(radix.FlatCmd(
-		&lenAfterPush,
-		"LPUSHX",
-		l.key,
-		elems...,
-	))
-	return
+func (l *List) PushX(elems ...interface{}) (lenAfterPush int, err error) {
+	return l.push("LPUSHX", elems)
 }
 
 // Range (LRANGE) Returns the specified elements of the list stored at key.
@@ -100,14 +269,17 @@ func (l *List) PushX(elems ...interface{}) (lenAfterPush int) {
 // Time complexity: O(S+N) where S is the distance of start offset from HEAD for small
 //                  lists, from nearest end (HEAD or TAIL) for large lists; and N is
 //                  the number of elements in the specified range.
-func (l *List) Range(start, stop int) (elems []string) {
-	l.cyclone.Raw.Do(radix.Cmd(
+func (l *List) Range(start, stop int) (elems []string, err error) {
+	err = l.cyclone.do(radix.Cmd(
 		&elems,
 		"LRANGE",
 		l.key,
 		strconv.Itoa(start),
 		strconv.Itoa(stop),
 	))
+	if err == nil {
+		err = l.cyclone.restoreAll(elems)
+	}
 	return
 }
 
@@ -121,8 +293,8 @@ func (l *List) Range(start, stop int) (elems []string) {
 //
 // Time complexity: O(N+M) where N is the length of the list and M is the
 //                  number of elements removed.
-func (l *List) Rem(count int, elem string) (removedElems int) {
-	l.cyclone.Raw.Do(radix.Cmd(
+func (l *List) Rem(count int, elem string) (removedElems int, err error) {
+	err = l.cyclone.do(radix.Cmd(
 		&removedElems,
 		"LREM",
 		l.key,
@@ -132,20 +304,24 @@ func (l *List) Rem(count int, elem string) (removedElems int) {
 	return
 }
 
-// Set sets the list element at index to element.
+// Set sets the list element at index to element. ErrNoSuchKey is returned
+// when the list does not exist and ErrIndexOutOfRange for out of range indexes.
 // https://redis.io/commands/lset
 //
 // Time complexity: O(N) where N is the length of the list. Setting either
 //                  the first or the last element of the list is O(1).
-func (l *List) Set(index int, elem string) bool {
-	err := l.cyclone.Raw.Do(radix.Cmd(
+func (l *List) Set(index int, elem string) error {
+	value, err := l.cyclone.encodeValue(elem)
+	if err != nil {
+		return err
+	}
+	return l.cyclone.do(radix.Cmd(
 		nil,
 		"LSET",
 		l.key,
 		strconv.Itoa(index),
-		elem,
+		value,
 	))
-	return err == nil
 }
 
 // Trim (LTRIM) Trim an existing list so that it will contain only the specified
@@ -154,27 +330,68 @@ func (l *List) Set(index int, elem string) bool {
 // https://redis.io/commands/ltrim
 //
 // Time complexity: O(N) where N is the number of elements to be removed by the operation.
-func (l *List) Trim(start, stop int) bool {
-	err := l.cyclone.Raw.Do(radix.Cmd(
+func (l *List) Trim(start, stop int) error {
+	return l.cyclone.do(radix.Cmd(
 		nil,
 		"LTRIM",
 		l.key,
 		strconv.Itoa(start),
 		strconv.Itoa(stop),
 	))
-	return err == nil
 }
 
 // RPop removes and returns the last element of the list stored at key.
+// ErrNil is returned when the list is empty.
 // https://redis.io/commands/rpop
 //
 // Time complexity: O(1)
-func (l *List) RPop() (elem string) {
-	l.cyclone.Raw.Do(radix.Cmd(&elem, "RPOP", l.key))
+func (l *List) RPop() (elem string, err error) {
+	mn := radix.MaybeNil{Rcv: &elem}
+	err = l.cyclone.do(radix.Cmd(&mn, "RPOP", l.key))
+	if err = nilErr(&mn, err); err != nil {
+		return
+	}
+	return l.cyclone.restore(elem)
+}
+
+// RPopInto is equal to RPop, but it decodes element into v with codec
+// (or radix when codec is not set), see WithCodec.
+// https://redis.io/commands/rpop
+//
+// Time complexity: O(1)
+func (l *List) RPopInto(v interface{}) error {
+	return l.cyclone.doInto(v, "RPOP", l.key)
+}
+
+// RPopN (RPOP with count) Removes and returns up to count elements from the tail
+// of the list stored at key. ErrNil is returned when the list is empty.
+// https://redis.io/commands/rpop
+//
+// Time complexity: O(N) where N is the number of elements returned
+func (l *List) RPopN(count int) (elems []string, err error) {
+	mn := radix.MaybeNil{Rcv: &elems}
+	err = l.cyclone.do(radix.Cmd(&mn, "RPOP", l.key, strconv.Itoa(count)))
+	if err = nilErr(&mn, err); err == nil {
+		err = l.cyclone.restoreAll(elems)
+	}
 	return
 }
 
-//TODO: RPOPLPUSH
+// RPopLPush (RPOPLPUSH) Atomically returns and removes the last element (tail)
+// of the list stored at source, and pushes the element at the first element
+// (head) of the list stored at destination. ErrNil is returned when
+// source is empty.
+// https://redis.io/commands/rpoplpush
+//
+// Time complexity: O(1)
+func (l *List) RPopLPush(dst *List) (elem string, err error) {
+	mn := radix.MaybeNil{Rcv: &elem}
+	err = l.cyclone.do(radix.Cmd(&mn, "RPOPLPUSH", l.key, dst.key))
+	if err = nilErr(&mn, err); err != nil {
+		return
+	}
+	return l.cyclone.restore(elem)
+}
 
 // RPush inserts all the specified values at the tail of the list stored at key.
 // If key does not exist, it is created as empty list before performing the push operation.
@@ -182,14 +399,8 @@ func (l *List) RPop() (elem string) {
 //
 // Time complexity: O(1) for each element added, so O(N) to add N elements when
 //                  the command is called with multiple arguments.
-func (l *List) RPush(elems ...interface{}) (lenAfterPush int) {
-	l.cyclone.Raw.Do(radix.FlatCmd(
-		&lenAfterPush,
-		"RPUSH",
-		l.key,
-		elems...,
-	))
-	return
+func (l *List) RPush(elems ...interface{}) (lenAfterPush int, err error) {
+	return l.push("RPUSH", elems)
 }
 
 // RPushX inserts specified values at the tail of the list stored at key, only
@@ -199,12 +410,91 @@ func (l *List) RPush(elems ...interface{}) (lenAfterPush int) {
 //
 // Time complexity: O(1) for each element added, so O(N) to add N elements
 //                  when the command is called with multiple arguments.
-func (l *List) RPushX(elems ...interface{}) (lenAfterPush int) {
-	l.cyclone.Raw.Do(radix.FlatCmd(
-		&lenAfterPush,
-		"RPUSHX",
-		l.key,
-		elems...,
-	))
+func (l *List) RPushX(elems ...interface{}) (lenAfterPush int, err error) {
+	return l.push("RPUSHX", elems)
+}
+
+// WithCodec returns copy of the list wrapper which encodes written elements
+// with codec and decodes them in Into methods.
+//
+//   redis.List("jobs").WithCodec(cyclone.JSON).RPush(job)
+//   redis.List("jobs").WithCodec(cyclone.JSON).PopInto(&job)
+//
+func (l *List) WithCodec(codec Codec) *List {
+	return &List{Key: Key{cyclone: l.cyclone.WithCodec(codec), key: l.key}}
+}
+
+// WithTransformer returns copy of the list wrapper which transforms written elements
+// with t and restores read elements, see Cyclone.WithTransformer.
+func (l *List) WithTransformer(t Transformer) *List {
+	return &List{Key: Key{cyclone: l.cyclone.WithTransformer(t), key: l.key}}
+}
+
+// Rank sets RANK option, rank of the first match to return. Negative rank
+// searches from the tail to the head.
+// https://redis.io/commands/lpos
+//
+func (p *ListPos) Rank(rank int) *ListPos {
+	p.args = append(p.args, "RANK", strconv.Itoa(rank))
+	return p
+}
+
+// MaxLen sets MAXLEN option, only the first maxLen elements are compared.
+// https://redis.io/commands/lpos
+//
+func (p *ListPos) MaxLen(maxLen int) *ListPos {
+	p.args = append(p.args, "MAXLEN", strconv.Itoa(maxLen))
+	return p
+}
+
+// Index returns index of the first matching element.
+// ErrNil is returned when no element matches.
+func (p *ListPos) Index() (index int, err error) {
+	mn := radix.MaybeNil{Rcv: &index}
+	err = p.list.cyclone.do(radix.Cmd(&mn, "LPOS", p.cmdArgs()...))
+	err = nilErr(&mn, err)
+	return
+}
+
+// Indexes returns indexes of up to count matching elements (COUNT option).
+// Count of zero returns all matching elements.
+func (p *ListPos) Indexes(count int) (indexes []int, err error) {
+	args := append(p.cmdArgs(), "COUNT", strconv.Itoa(count))
+	err = p.list.cyclone.do(radix.Cmd(&indexes, "LPOS", args...))
+	return
+}
+
+func (p *ListPos) cmdArgs() []string {
+	args := make([]string, 0, len(p.args)+4)
+	args = append(args, p.list.key, p.elem)
+	return append(args, p.args...)
+}
+
+// push pushes elems encoded with codec using cmd.
+func (l *List) push(cmd string, elems []interface{}) (lenAfterPush int, err error) {
+	elems, err = l.cyclone.encode(elems, 0, 1)
+	if err != nil {
+		return 0, err
+	}
+	err = l.cyclone.do(radix.FlatCmd(&lenAfterPush, cmd, l.key, elems...))
+	return
+}
+
+func (l *List) bpop(cmd string, timeout time.Duration, others []*List) (key, elem string, err error) {
+	args := make([]string, 0, len(others)+2)
+	args = append(args, l.key)
+	for _, other := range others {
+		args = append(args, other.key)
+	}
+	args = append(args, formatTimeout(timeout))
+
+	var reply []string
+	mn := radix.MaybeNil{Rcv: &reply}
+	err = l.cyclone.doBlocking(radix.Cmd(&mn, cmd, args...))
+	err = nilErr(&mn, err)
+	if err == nil && len(reply) == 2 {
+		key = reply[0]
+		elem, err = l.cyclone.restore(reply[1])
+	}
 	return
 }
@@ -1,7 +1,11 @@
 package cyclone
 
 import (
+	"context"
+	"fmt"
+	"os"
 	"testing"
+	"time"
 
 	. "github.com/franela/goblin"
 	"github.com/mediocregopher/radix/v3"
@@ -9,19 +13,138 @@ import (
 
 func TestList(t *testing.T) {
 	g := Goblin(t)
+
+	g.Describe(".BPop", func() {
+		g.It("Rounds sub-millisecond timeout up", func() {
+			var timeout string
+			stub := radix.Stub("tcp", "127.0.0.1:6379", func(args []string) interface{} {
+				timeout = args[len(args)-1]
+				return nil
+			})
+			c := New(stub)
+			defer c.Close()
+
+			_, _, err := c.List("ListBLPopShort").BPop(500 * time.Microsecond)
+			g.Assert(err).Eql(ErrNil)
+			g.Assert(timeout).Eql("0.001")
+		})
+	})
+
 	withConn(func(c *Cyclone) {
+		g.Describe(".BMove", func() {
+			g.It("Moves element between lists", func() {
+				src := c.List("ListBLMoveSrc")
+				dst := c.List("ListBLMoveDst")
+				src.RPush("a", "b")
+
+				elem, err := src.BMove(dst, Left, Right, time.Second)
+				g.Assert(err).Eql(nil)
+				g.Assert(elem).Eql("a")
+
+				elems, _ := dst.Range(0, -1)
+				g.Assert(elems).Eql([]string{"a"})
+			})
+		})
+
 		g.Describe(".BPop", func() {
-			g.Xit("", func() {
+			g.It("Pops element from HEAD of the first non-empty list", func() {
+				first := c.List("ListBLPopFirst")
+				second := c.List("ListBLPopSecond")
+				second.RPush("a", "b")
+
+				key, elem, err := first.BPop(time.Second, second)
+				g.Assert(err).Eql(nil)
+				g.Assert(key).Eql("ListBLPopSecond")
+				g.Assert(elem).Eql("a")
+			})
+
+			g.It("Waits for element to be pushed", func() {
+				list := c.List("ListBLPopWait")
+				go func() {
+					time.Sleep(50 * time.Millisecond)
+					list.RPush("a")
+				}()
+
+				key, elem, err := list.BPop(2 * time.Second)
+				g.Assert(err).Eql(nil)
+				g.Assert(key).Eql("ListBLPopWait")
+				g.Assert(elem).Eql("a")
+			})
+
+			g.It("Returns ErrNil on timeout", func() {
+				_, _, err := c.List("ListBLPopTimeout").BPop(100 * time.Millisecond)
+				g.Assert(err).Eql(ErrNil)
+			})
+
+			g.It("Is interrupted by context", func() {
+				ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+				defer cancel()
+
+				_, _, err := c.WithContext(ctx).List("ListBLPopCtx").BPop(0)
+				g.Assert(err).Eql(context.DeadlineExceeded)
+			})
+
+			g.It("Uses dedicated connections when connected with options", func() {
+				conn, _ := Connect(Options{
+					Addr:        fmt.Sprintf("%s:%s", os.Getenv("REDIS_HOST"), os.Getenv("REDIS_PORT")),
+					PoolSize:    1,
+					ReadTimeout: 50 * time.Millisecond,
+				})
+				defer conn.Close()
+
+				// read timeout of the pool does not interrupt blocking pop
+				_, _, err := conn.List("ListBLPopDedicated").BPop(200 * time.Millisecond)
+				g.Assert(err).Eql(ErrNil)
+
+				// shared pool stays available while other pop blocks
+				done := make(chan error)
+				go func() {
+					_, _, err := conn.List("ListBLPopDedicated").BPop(time.Second)
+					done <- err
+				}()
+				time.Sleep(50 * time.Millisecond)
+				conn.List("ListBLPopDedicated").RPush("a")
+				g.Assert(<-done).Eql(nil)
 			})
 		})
 
 		g.Describe(".BRPop", func() {
-			g.Xit("", func() {
+			g.It("Pops element from TAIL of the first non-empty list", func() {
+				first := c.List("ListBRPopFirst")
+				second := c.List("ListBRPopSecond")
+				first.RPush("a", "b")
+				second.RPush("c")
+
+				key, elem, err := first.BRPop(time.Second, second)
+				g.Assert(err).Eql(nil)
+				g.Assert(key).Eql("ListBRPopFirst")
+				g.Assert(elem).Eql("b")
+			})
+
+			g.It("Returns ErrNil on timeout", func() {
+				_, _, err := c.List("ListBRPopTimeout").BRPop(100 * time.Millisecond)
+				g.Assert(err).Eql(ErrNil)
 			})
 		})
 
 		g.Describe(".BRPopLPush", func() {
-			g.Xit("", func() {
+			g.It("Pops element from TAIL and pushes it to HEAD of other list", func() {
+				src := c.List("ListBRPopLPushSrc")
+				dst := c.List("ListBRPopLPushDst")
+				src.RPush("a", "b")
+				dst.RPush("c")
+
+				elem, err := src.BRPopLPush(dst, time.Second)
+				g.Assert(err).Eql(nil)
+				g.Assert(elem).Eql("b")
+
+				elems, _ := dst.Range(0, -1)
+				g.Assert(elems).Eql([]string{"b", "c"})
+			})
+
+			g.It("Returns ErrNil on timeout", func() {
+				_, err := c.List("ListBRPopLPushTimeout").BRPopLPush(c.List("ListBRPopLPushTimeoutDst"), 100*time.Millisecond)
+				g.Assert(err).Eql(ErrNil)
 			})
 		})
 
@@ -30,18 +153,42 @@ func TestList(t *testing.T) {
 				list := c.List("ListLIndex")
 
 				// empty list
-				elem := list.Index(0)
+				elem, err := list.Index(0)
 				g.Assert(elem).Eql("")
+				g.Assert(err).Eql(ErrNil)
 
 				// with elments
-				g.Assert(list.Push("a", "b")).Eql(2)
-				elem = list.Index(1)
+				length, _ := list.Push("a", "b")
+				g.Assert(length).Eql(2)
+				elem, err = list.Index(1)
 				g.Assert(elem).Eql("a")
+				g.Assert(err).Eql(nil)
+			})
+
+			g.It("Returns ErrWrongType for non-list keys", func() {
+				c.Raw.Do(radix.Cmd(nil, "HSET", "ListLIndexHash", "a", "1"))
+
+				_, err := c.List("ListLIndexHash").Index(0)
+				g.Assert(err).Eql(ErrWrongType)
 			})
 		})
 
 		g.Describe(".Insert", func() {
-			g.Xit("", func() {
+			g.It("Inserts element before or after pivot", func() {
+				list := c.List("ListLInsert")
+				list.RPush("a", "c")
+
+				length, _ := list.Insert(Before, "c", "b")
+				g.Assert(length).Eql(3)
+				length, _ = list.Insert(After, "c", "d")
+				g.Assert(length).Eql(4)
+
+				elems, _ := list.Range(0, -1)
+				g.Assert(elems).Eql([]string{"a", "b", "c", "d"})
+
+				// missing pivot
+				length, _ = list.Insert(After, "x", "y")
+				g.Assert(length).Eql(-1)
 			})
 		})
 
@@ -49,39 +196,112 @@ func TestList(t *testing.T) {
 			g.It("Returns len of the list", func() {
 				c.List("ListLen").Push("a", "b", "c")
 
-				length := c.List("ListLen").Len()
+				length, _ := c.List("ListLen").Len()
 				g.Assert(length).Eql(3)
 			})
 		})
 
+		g.Describe(".Move", func() {
+			g.It("Moves element between lists", func() {
+				src := c.List("ListLMoveSrc")
+				dst := c.List("ListLMoveDst")
+				src.RPush("a", "b")
+				dst.RPush("c")
+
+				elem, err := src.Move(dst, Right, Left)
+				g.Assert(err).Eql(nil)
+				g.Assert(elem).Eql("b")
+
+				elems, _ := dst.Range(0, -1)
+				g.Assert(elems).Eql([]string{"b", "c"})
+
+				_, err = c.List("ListLMoveEmpty").Move(dst, Left, Left)
+				g.Assert(err).Eql(ErrNil)
+			})
+		})
+
 		g.Describe(".Pop", func() {
 			g.It("Pops element from HEAD", func() {
 				list := c.List("ListLPop")
 
 				// empty list
-				elem := list.Pop()
+				elem, err := list.Pop()
 				g.Assert(elem).Eql("")
+				g.Assert(err).Eql(ErrNil)
 
 				list.Push("a", "b")
-				elem = list.Pop()
+				elem, err = list.Pop()
 				g.Assert(elem).Eql("b")
-				g.Assert(list.Range(0, -1)).Eql([]string{"a"})
+				g.Assert(err).Eql(nil)
+				elems, _ := list.Range(0, -1)
+				g.Assert(elems).Eql([]string{"a"})
+			})
+		})
+
+		g.Describe(".PopInto", func() {
+			g.It("Pops element into value without codec", func() {
+				list := c.List("ListPopInto")
+				list.Push(1, 2)
+
+				var n int
+				g.Assert(list.PopInto(&n)).Eql(nil)
+				g.Assert(n).Eql(2)
+				g.Assert(list.RPopInto(&n)).Eql(nil)
+				g.Assert(n).Eql(1)
+				g.Assert(list.PopInto(&n)).Eql(ErrNil)
+				g.Assert(list.RPopInto(&n)).Eql(ErrNil)
+			})
+		})
+
+		g.Describe(".PopN", func() {
+			g.It("Pops count elements from HEAD", func() {
+				list := c.List("ListLPopCount")
+
+				// empty list
+				_, err := list.PopN(2)
+				g.Assert(err).Eql(ErrNil)
+
+				list.RPush("a", "b", "c")
+				elems, err := list.PopN(2)
+				g.Assert(err).Eql(nil)
+				g.Assert(elems).Eql([]string{"a", "b"})
 			})
 		})
 
 		g.Describe(".Pos", func() {
-			g.Xit("", func() {
+			g.It("Returns index of matching elements", func() {
+				list := c.List("ListLPos")
+				list.RPush("a", "b", "c", "1", "2", "3", "c", "c")
+
+				index, err := list.Pos("c").Index()
+				g.Assert(err).Eql(nil)
+				g.Assert(index).Eql(2)
+
+				index, _ = list.Pos("c").Rank(-1).Index()
+				g.Assert(index).Eql(7)
+
+				indexes, _ := list.Pos("c").Indexes(2)
+				g.Assert(indexes).Eql([]int{2, 6})
+
+				indexes, _ = list.Pos("c").Rank(2).Indexes(0)
+				g.Assert(indexes).Eql([]int{6, 7})
+
+				indexes, _ = list.Pos("c").MaxLen(4).Indexes(0)
+				g.Assert(indexes).Eql([]int{2})
+
+				_, err = list.Pos("x").Index()
+				g.Assert(err).Eql(ErrNil)
 			})
 		})
 
 		g.Describe(".Push", func() {
 			g.It("Pushes elements into list HEAD", func() {
 				c.List("ListLPush").Push("a", "b", "c")
-				length := c.List("ListLPush").Push("d")
+				length, _ := c.List("ListLPush").Push("d")
 
 				g.Assert(length).Eql(4)
 
-				elems := c.List("ListLPush").Range(0, -1)
+				elems, _ := c.List("ListLPush").Range(0, -1)
 				g.Assert(elems[0]).Eql("d")
 				g.Assert(elems[1]).Eql("c")
 				g.Assert(elems[2]).Eql("b")
@@ -92,17 +312,17 @@ func TestList(t *testing.T) {
 		g.Describe(".PushX", func() {
 			g.It("Pushes elements into list HEAD only if list exists", func() {
 				c.List("ListLPushX").Push("a")
-				lenExisting := c.List("ListLPushX").PushX("b")
-				lenNonExisting := c.List("ListLPushXother").PushX("c")
+				lenExisting, _ := c.List("ListLPushX").PushX("b")
+				lenNonExisting, _ := c.List("ListLPushXother").PushX("c")
 
 				g.Assert(lenExisting).Eql(2)
 				g.Assert(lenNonExisting).Eql(0)
 
-				elems := c.List("ListLPushX").Range(0, -1)
+				elems, _ := c.List("ListLPushX").Range(0, -1)
 				g.Assert(elems[0]).Eql("b")
 				g.Assert(elems[1]).Eql("a")
 
-				elems = c.List("ListLPushXother").Range(0, -1)
+				elems, _ = c.List("ListLPushXother").Range(0, -1)
 				g.Assert(len(elems)).Eql(0)
 			})
 		})
@@ -112,7 +332,7 @@ func TestList(t *testing.T) {
 				c.Raw.Do(radix.Cmd(nil, "RPUSH", "ListLRange", "a", "b", "c"))
 
 				// full range
-				elems := c.List("ListLRange").Range(0, -1)
+				elems, _ := c.List("ListLRange").Range(0, -1)
 
 				g.Assert(len(elems)).Equal(3)
 				g.Assert(elems[0]).Eql("a")
@@ -120,13 +340,13 @@ func TestList(t *testing.T) {
 				g.Assert(elems[2]).Eql("c")
 
 				// single element
-				elems = c.List("ListLRange").Range(1, 1)
+				elems, _ = c.List("ListLRange").Range(1, 1)
 
 				g.Assert(len(elems)).Equal(1)
 				g.Assert(elems[0]).Eql("b")
 
 				// out of bounds
-				elems = c.List("ListLRange").Range(4, 6)
+				elems, _ = c.List("ListLRange").Range(4, 6)
 
 				g.Assert(len(elems)).Equal(0)
 			})
@@ -137,42 +357,33 @@ func TestList(t *testing.T) {
 				list := c.List("ListLRem")
 
 				// empty list
-				g.Assert(
-					list.Rem(0, "a"),
-				).Eql(0)
+				removed, _ := list.Rem(0, "a")
+				g.Assert(removed).Eql(0)
 
 				// count == 0
 				list.Push("a", "b", "a", "b")
-				g.Assert(
-					list.Rem(0, "a"),
-				).Eql(2)
-				g.Assert(
-					list.Range(0, -1),
-				).Eql([]string{"b", "b"})
+				removed, _ = list.Rem(0, "a")
+				g.Assert(removed).Eql(2)
+				elems, _ := list.Range(0, -1)
+				g.Assert(elems).Eql([]string{"b", "b"})
 
 				// count > 0
 				list.Push("c", "b", "b")
-				g.Assert(
-					list.Range(0, -1),
-				).Eql([]string{"b", "b", "c", "b", "b"})
-				g.Assert(
-					list.Rem(2, "b"),
-				).Eql(2)
-				g.Assert(
-					list.Range(0, -1),
-				).Eql([]string{"c", "b", "b"})
+				elems, _ = list.Range(0, -1)
+				g.Assert(elems).Eql([]string{"b", "b", "c", "b", "b"})
+				removed, _ = list.Rem(2, "b")
+				g.Assert(removed).Eql(2)
+				elems, _ = list.Range(0, -1)
+				g.Assert(elems).Eql([]string{"c", "b", "b"})
 
 				// count < 0
 				list.Push("b")
-				g.Assert(
-					list.Range(0, -1),
-				).Eql([]string{"b", "c", "b", "b"})
-				g.Assert(
-					list.Rem(-2, "b"),
-				).Eql(2)
-				g.Assert(
-					list.Range(0, -1),
-				).Eql([]string{"b", "c"})
+				elems, _ = list.Range(0, -1)
+				g.Assert(elems).Eql([]string{"b", "c", "b", "b"})
+				removed, _ = list.Rem(-2, "b")
+				g.Assert(removed).Eql(2)
+				elems, _ = list.Range(0, -1)
+				g.Assert(elems).Eql([]string{"b", "c"})
 			})
 		})
 
@@ -183,21 +394,24 @@ func TestList(t *testing.T) {
 				// empty list
 				g.Assert(
 					list.Set(0, "a"),
-				).Eql(false)
+				).Eql(ErrNoSuchKey)
 
 				// add some data
 				list.Push("b", "a")
-				g.Assert(
-					list.Range(0, -1),
-				).Eql([]string{"a", "b"})
+				elems, _ := list.Range(0, -1)
+				g.Assert(elems).Eql([]string{"a", "b"})
 
 				// with existing index
 				g.Assert(
 					list.Set(1, "c"),
-				).Eql(true)
+				).Eql(nil)
+				elems, _ = list.Range(0, -1)
+				g.Assert(elems).Eql([]string{"a", "c"})
+
+				// out of range
 				g.Assert(
-					list.Range(0, -1),
-				).Eql([]string{"a", "c"})
+					list.Set(5, "d"),
+				).Eql(ErrIndexOutOfRange)
 			})
 		})
 
@@ -208,17 +422,15 @@ func TestList(t *testing.T) {
 
 				g.Assert(
 					list.Trim(0, 3),
-				).Eql(true)
-				g.Assert(
-					list.Range(0, -1),
-				).Eql([]string{"a", "b", "c", "d"})
+				).Eql(nil)
+				elems, _ := list.Range(0, -1)
+				g.Assert(elems).Eql([]string{"a", "b", "c", "d"})
 
 				g.Assert(
 					list.Trim(1, -3),
-				).Eql(true)
-				g.Assert(
-					list.Range(0, -1),
-				).Eql([]string{"b"})
+				).Eql(nil)
+				elems, _ = list.Range(0, -1)
+				g.Assert(elems).Eql([]string{"b"})
 			})
 		})
 
@@ -227,29 +439,57 @@ func TestList(t *testing.T) {
 				list := c.List("ListRPop")
 
 				// empty list
-				elem := list.RPop()
+				elem, err := list.RPop()
 				g.Assert(elem).Eql("")
+				g.Assert(err).Eql(ErrNil)
 
 				list.RPush("a", "b")
-				elem = list.RPop()
+				elem, err = list.RPop()
 				g.Assert(elem).Eql("b")
-				g.Assert(list.Range(0, -1)).Eql([]string{"a"})
+				g.Assert(err).Eql(nil)
+				elems, _ := list.Range(0, -1)
+				g.Assert(elems).Eql([]string{"a"})
+			})
+		})
+
+		g.Describe(".RPopN", func() {
+			g.It("Pops count elements from TAIL", func() {
+				list := c.List("ListRPopCount")
+				list.RPush("a", "b", "c")
+
+				elems, err := list.RPopN(2)
+				g.Assert(err).Eql(nil)
+				g.Assert(elems).Eql([]string{"c", "b"})
 			})
 		})
 
 		g.Describe(".RPopLPush", func() {
-			g.Xit("", func() {
+			g.It("Pops element from TAIL and pushes it to HEAD of other list", func() {
+				src := c.List("ListRPopLPushSrc")
+				dst := c.List("ListRPopLPushDst")
+
+				// empty list
+				_, err := src.RPopLPush(dst)
+				g.Assert(err).Eql(ErrNil)
+
+				src.RPush("a", "b")
+				elem, err := src.RPopLPush(dst)
+				g.Assert(err).Eql(nil)
+				g.Assert(elem).Eql("b")
+
+				elems, _ := dst.Range(0, -1)
+				g.Assert(elems).Eql([]string{"b"})
 			})
 		})
 
 		g.Describe(".RPush", func() {
 			g.It("Pushes elements into list TAIL", func() {
 				c.List("ListRPush").Push("a", "b", "c")
-				length := c.List("ListRPush").RPush("d")
+				length, _ := c.List("ListRPush").RPush("d")
 
 				g.Assert(length).Eql(4)
 
-				elems := c.List("ListRPush").Range(0, -1)
+				elems, _ := c.List("ListRPush").Range(0, -1)
 				g.Assert(elems[0]).Eql("c")
 				g.Assert(elems[1]).Eql("b")
 				g.Assert(elems[2]).Eql("a")
@@ -260,17 +500,17 @@ func TestList(t *testing.T) {
 		g.Describe(".RPushX", func() {
 			g.It("Pushes elements into list TAIL only if list exists", func() {
 				c.List("ListRPushX").RPush("a")
-				lenExisting := c.List("ListRPushX").RPushX("b")
-				lenNonExisting := c.List("ListRPushXother").RPushX("c")
+				lenExisting, _ := c.List("ListRPushX").RPushX("b")
+				lenNonExisting, _ := c.List("ListRPushXother").RPushX("c")
 
 				g.Assert(lenExisting).Eql(2)
 				g.Assert(lenNonExisting).Eql(0)
 
-				elems := c.List("ListRPushX").Range(0, -1)
+				elems, _ := c.List("ListRPushX").Range(0, -1)
 				g.Assert(elems[0]).Eql("a")
 				g.Assert(elems[1]).Eql("b")
 
-				elems = c.List("ListRPushXother").Range(0, -1)
+				elems, _ = c.List("ListRPushXother").Range(0, -1)
 				g.Assert(len(elems)).Eql(0)
 			})
 		})
@@ -0,0 +1,193 @@
+package cyclone
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mediocregopher/radix/v3"
+)
+
+const (
+	// DefaultAddr is used by Connect when Options.Addr is empty.
+	DefaultAddr = "127.0.0.1:6379"
+
+	// DefaultPoolSize is used by Connect when Options.PoolSize is not positive.
+	DefaultPoolSize = 10
+)
+
+// Options configures connection created by Connect.
+type Options struct {
+	// Addr is host:port of redis server. Defaults to DefaultAddr.
+	Addr string
+
+	// Username is used for ACL based authentication (redis 6+).
+	// Password alone is used with the legacy AUTH when Username is empty.
+	Username string
+	Password string
+
+	// DB is the database selected after connecting.
+	DB int
+
+	// TLSConfig enables TLS when not nil.
+	TLSConfig *tls.Config
+
+	// DialTimeout, ReadTimeout and WriteTimeout default to radix defaults
+	// (10 seconds) when zero.
+	DialTimeout  time.Duration
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+
+	// PoolSize is the number of connections kept in the pool.
+	// Defaults to DefaultPoolSize.
+	PoolSize int
+}
+
+// ParseURL parses redis:// or rediss:// (TLS) URL into Options.
+//
+//   redis://[[username]:password@]host[:port][/db][?option=value]
+//
+// Supported query options are: db, username, password, dial_timeout,
+// read_timeout, write_timeout (as time.ParseDuration strings) and pool_size.
+func ParseURL(rawurl string) (Options, error) {
+	var opts Options
+
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return opts, err
+	}
+
+	switch u.Scheme {
+	case "redis":
+	case "rediss":
+		opts.TLSConfig = &tls.Config{ServerName: u.Hostname()}
+	default:
+		return opts, fmt.Errorf("cyclone: invalid URL scheme %q", u.Scheme)
+	}
+
+	opts.Addr = u.Host
+	if u.Port() == "" && u.Host != "" {
+		opts.Addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+
+	q := u.Query()
+	opts.Username = q.Get("username")
+	opts.Password = q.Get("password")
+	if u.User != nil {
+		if name := u.User.Username(); name != "" {
+			opts.Username = name
+		}
+		if pass, ok := u.User.Password(); ok {
+			opts.Password = pass
+		}
+	}
+
+	db := q.Get("db")
+	if path := strings.Trim(u.Path, "/"); path != "" {
+		db = path
+	}
+	if db != "" {
+		if opts.DB, err = strconv.Atoi(db); err != nil {
+			return opts, fmt.Errorf("cyclone: invalid database %q", db)
+		}
+	}
+
+	durations := map[string]*time.Duration{
+		"dial_timeout":  &opts.DialTimeout,
+		"read_timeout":  &opts.ReadTimeout,
+		"write_timeout": &opts.WriteTimeout,
+	}
+	for name, dst := range durations {
+		if v := q.Get(name); v != "" {
+			if *dst, err = time.ParseDuration(v); err != nil {
+				return opts, fmt.Errorf("cyclone: invalid %s %q", name, v)
+			}
+		}
+	}
+
+	if v := q.Get("pool_size"); v != "" {
+		if opts.PoolSize, err = strconv.Atoi(v); err != nil {
+			return opts, fmt.Errorf("cyclone: invalid pool_size %q", v)
+		}
+	}
+
+	return opts, nil
+}
+
+// Connect creates a connection pool configured by opts.
+// Blocking commands (BLPOP, BRPOP, ...) use separate dedicated connections,
+// up to PoolSize of them are kept idle for reuse.
+// Error is returned when initial connection cannot be established.
+func Connect(opts Options) (*Cyclone, error) {
+	addr := opts.Addr
+	if addr == "" {
+		addr = DefaultAddr
+	}
+	size := opts.PoolSize
+	if size <= 0 {
+		size = DefaultPoolSize
+	}
+
+	dialOpts := opts.dialOpts()
+	connFunc := func(network, addr string) (radix.Conn, error) {
+		return radix.Dial(network, addr, dialOpts...)
+	}
+
+	pool, err := radix.NewPool("tcp", addr, size, radix.PoolConnFunc(connFunc))
+	if err != nil {
+		return nil, err
+	}
+
+	// blocking commands wait on the server side, read timeout would
+	// interrupt them before their own timeout
+	blockingOpts := append(opts.dialOpts(), radix.DialReadTimeout(0))
+	c := New(pool)
+	c.blocking = newConnPool(size, func() (radix.Conn, error) {
+		return radix.Dial("tcp", addr, blockingOpts...)
+	})
+	return c, nil
+}
+
+// ConnectURL is a shortcut for ParseURL followed by Connect.
+func ConnectURL(rawurl string) (*Cyclone, error) {
+	opts, err := ParseURL(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	return Connect(opts)
+}
+
+// dialOpts translates Options into radix dial options.
+func (o Options) dialOpts() []radix.DialOpt {
+	var opts []radix.DialOpt
+
+	if o.DialTimeout > 0 {
+		opts = append(opts, radix.DialConnectTimeout(o.DialTimeout))
+	}
+	if o.ReadTimeout > 0 {
+		opts = append(opts, radix.DialReadTimeout(o.ReadTimeout))
+	}
+	if o.WriteTimeout > 0 {
+		opts = append(opts, radix.DialWriteTimeout(o.WriteTimeout))
+	}
+
+	switch {
+	case o.Username != "":
+		opts = append(opts, radix.DialAuthUser(o.Username, o.Password))
+	case o.Password != "":
+		opts = append(opts, radix.DialAuthPass(o.Password))
+	}
+
+	if o.DB != 0 {
+		opts = append(opts, radix.DialSelectDB(o.DB))
+	}
+	if o.TLSConfig != nil {
+		opts = append(opts, radix.DialUseTLS(o.TLSConfig))
+	}
+
+	return opts
+}
@@ -0,0 +1,160 @@
+package cyclone
+
+import (
+	"strconv"
+
+	"github.com/mediocregopher/radix/v3"
+)
+
+// PipeHash queues redis hash operations in a Pipe.
+// Methods mirror Hash, see Hash for their documentation.
+type PipeHash struct {
+	pipe *Pipe
+	key  string
+}
+
+// Del queues HDEL, see Hash.Del.
+func (l *PipeHash) Del(fields ...interface{}) *IntResult {
+	r := &IntResult{result: pending()}
+	l.pipe.queue(radix.FlatCmd(&r.val, "HDEL", l.key, fields...), r.done)
+	return r
+}
+
+// Exists queues HEXISTS, see Hash.Exists.
+func (l *PipeHash) Exists(field string) *BoolResult {
+	r := &BoolResult{result: pending()}
+	var exists int
+	l.pipe.queue(radix.Cmd(&exists, "HEXISTS", l.key, field), func(err error) error {
+		r.val = exists == 1
+		return r.done(err)
+	})
+	return r
+}
+
+// Get queues HGET, see Hash.Get.
+func (l *PipeHash) Get(field string) *StringResult {
+	r := &StringResult{result: pending()}
+	mn := radix.MaybeNil{Rcv: &r.val}
+	l.pipe.queue(radix.Cmd(&mn, "HGET", l.key, field), func(err error) error {
+		if !mn.Nil {
+			err = l.pipe.restore(&r.val, err)
+		}
+		return r.done(err)
+	})
+	return r
+}
+
+// GetAll queues HGETALL, see Hash.GetAll.
+func (l *PipeHash) GetAll() *StringMapResult {
+	r := &StringMapResult{result: pending()}
+	l.pipe.queue(radix.Cmd(&r.val, "HGETALL", l.key), func(err error) error {
+		for field, value := range r.val {
+			if err = l.pipe.restore(&value, err); err != nil {
+				break
+			}
+			r.val[field] = value
+		}
+		return r.done(err)
+	})
+	return r
+}
+
+// Incr queues HINCRBY, see Hash.Incr.
+func (l *PipeHash) Incr(field string, by int) *IntResult {
+	r := &IntResult{result: pending()}
+	l.pipe.queue(radix.Cmd(
+		&r.val,
+		"HINCRBY",
+		l.key,
+		field,
+		strconv.FormatInt(int64(by), 10),
+	), r.done)
+	return r
+}
+
+// IncrFloat queues HINCRBYFLOAT, see Hash.IncrFloat.
+func (l *PipeHash) IncrFloat(field string, by float64) *FloatResult {
+	r := &FloatResult{result: pending()}
+	l.pipe.queue(radix.Cmd(
+		&r.val,
+		"HINCRBYFLOAT",
+		l.key,
+		field,
+		strconv.FormatFloat(by, 'E', -1, 64),
+	), r.done)
+	return r
+}
+
+// Keys queues HKEYS, see Hash.Keys.
+func (l *PipeHash) Keys() *StringsResult {
+	r := &StringsResult{result: pending()}
+	l.pipe.queue(radix.Cmd(&r.val, "HKEYS", l.key), r.done)
+	return r
+}
+
+// Len queues HLEN, see Hash.Len.
+func (l *PipeHash) Len() *IntResult {
+	r := &IntResult{result: pending()}
+	l.pipe.queue(radix.Cmd(&r.val, "HLEN", l.key), r.done)
+	return r
+}
+
+// MGet queues HMGET, see Hash.MGet.
+func (l *PipeHash) MGet(fields ...interface{}) *StringsResult {
+	r := &StringsResult{result: pending()}
+	var reply nullStrings
+	l.pipe.queue(radix.FlatCmd(&reply, "HMGET", l.key, fields...), func(err error) error {
+		if err == nil {
+			err = reply.restore(l.pipe.cyclone)
+		}
+		if err == nil {
+			r.val = reply.strings()
+		}
+		return r.done(err)
+	})
+	return r
+}
+
+// Set queues HSET, see Hash.Set.
+func (l *PipeHash) Set(kvpairs ...interface{}) *IntResult {
+	r := &IntResult{result: pending()}
+	kvpairs, err := l.pipe.cyclone.encode(kvpairs, 1, 2)
+	if err != nil {
+		l.pipe.reject(err, r.done)
+		return r
+	}
+	l.pipe.queue(radix.FlatCmd(&r.val, "HSET", l.key, kvpairs...), r.done)
+	return r
+}
+
+// SetNX queues HSETNX, see Hash.SetNX.
+func (l *PipeHash) SetNX(k, v string) *BoolResult {
+	r := &BoolResult{result: pending()}
+	value, err := l.pipe.cyclone.encodeValue(v)
+	if err != nil {
+		l.pipe.reject(err, r.done)
+		return r
+	}
+	var wasSet int
+	l.pipe.queue(radix.Cmd(&wasSet, "HSETNX", l.key, k, value), func(err error) error {
+		r.val = wasSet == 1
+		return r.done(err)
+	})
+	return r
+}
+
+// StrLen queues HSTRLEN, see Hash.StrLen.
+func (l *PipeHash) StrLen(field string) *IntResult {
+	r := &IntResult{result: pending()}
+	l.pipe.queue(radix.Cmd(&r.val, "HSTRLEN", l.key, field), r.done)
+	return r
+}
+
+// Vals queues HVALS, see Hash.Vals.
+func (l *PipeHash) Vals() *StringsResult {
+	r := &StringsResult{result: pending()}
+	l.pipe.queue(radix.Cmd(&r.val, "HVALS", l.key), func(err error) error {
+		return r.done(l.pipe.restoreAll(r.val, err))
+	})
+	return r
+}
@@ -0,0 +1,136 @@
+package cyclone
+
+import (
+	"strconv"
+
+	"github.com/mediocregopher/radix/v3"
+)
+
+// PipeList queues redis list operations in a Pipe.
+// Methods mirror List, see List for their documentation.
+type PipeList struct {
+	pipe *Pipe
+	key  string
+}
+
+// Index queues LINDEX, see List.Index.
+func (l *PipeList) Index(index int) *StringResult {
+	r := &StringResult{result: pending()}
+	mn := radix.MaybeNil{Rcv: &r.val}
+	l.pipe.queue(radix.Cmd(&mn, "LINDEX", l.key, strconv.Itoa(index)), func(err error) error {
+		return r.done(l.pipe.restore(&r.val, nilErr(&mn, err)))
+	})
+	return r
+}
+
+// Len queues LLEN, see List.Len.
+func (l *PipeList) Len() *IntResult {
+	r := &IntResult{result: pending()}
+	l.pipe.queue(radix.Cmd(&r.val, "LLEN", l.key), r.done)
+	return r
+}
+
+// Pop queues LPOP, see List.Pop.
+func (l *PipeList) Pop() *StringResult {
+	r := &StringResult{result: pending()}
+	mn := radix.MaybeNil{Rcv: &r.val}
+	l.pipe.queue(radix.Cmd(&mn, "LPOP", l.key), func(err error) error {
+		return r.done(l.pipe.restore(&r.val, nilErr(&mn, err)))
+	})
+	return r
+}
+
+// Push queues LPUSH, see List.Push.
+func (l *PipeList) Push(elems ...interface{}) *IntResult {
+	r := &IntResult{result: pending()}
+	l.push("LPUSH", elems, r)
+	return r
+}
+
+// PushX queues LPUSHX, see List.PushX.
+func (l *PipeList) PushX(elems ...interface{}) *IntResult {
+	r := &IntResult{result: pending()}
+	l.push("LPUSHX", elems, r)
+	return r
+}
+
+// Range queues LRANGE, see List.Range.
+func (l *PipeList) Range(start, stop int) *StringsResult {
+	r := &StringsResult{result: pending()}
+	l.pipe.queue(radix.Cmd(
+		&r.val,
+		"LRANGE",
+		l.key,
+		strconv.Itoa(start),
+		strconv.Itoa(stop),
+	), func(err error) error {
+		return r.done(l.pipe.restoreAll(r.val, err))
+	})
+	return r
+}
+
+// Rem queues LREM, see List.Rem.
+func (l *PipeList) Rem(count int, elem string) *IntResult {
+	r := &IntResult{result: pending()}
+	l.pipe.queue(radix.Cmd(
+		&r.val,
+		"LREM",
+		l.key,
+		strconv.Itoa(count),
+		elem,
+	), r.done)
+	return r
+}
+
+// Set queues LSET, see List.Set.
+func (l *PipeList) Set(index int, elem string) *StatusResult {
+	r := &StatusResult{result: pending()}
+	value, err := l.pipe.cyclone.encodeValue(elem)
+	if err != nil {
+		l.pipe.reject(err, r.done)
+		return r
+	}
+	l.pipe.queue(radix.Cmd(nil, "LSET", l.key, strconv.Itoa(index), value), r.done)
+	return r
+}
+
+// Trim queues LTRIM, see List.Trim.
+func (l *PipeList) Trim(start, stop int) *StatusResult {
+	r := &StatusResult{result: pending()}
+	l.pipe.queue(radix.Cmd(nil, "LTRIM", l.key, strconv.Itoa(start), strconv.Itoa(stop)), r.done)
+	return r
+}
+
+// RPop queues RPOP, see List.RPop.
+func (l *PipeList) RPop() *StringResult {
+	r := &StringResult{result: pending()}
+	mn := radix.MaybeNil{Rcv: &r.val}
+	l.pipe.queue(radix.Cmd(&mn, "RPOP", l.key), func(err error) error {
+		return r.done(l.pipe.restore(&r.val, nilErr(&mn, err)))
+	})
+	return r
+}
+
+// RPush queues RPUSH, see List.RPush.
+func (l *PipeList) RPush(elems ...interface{}) *IntResult {
+	r := &IntResult{result: pending()}
+	l.push("RPUSH", elems, r)
+	return r
+}
+
+// RPushX queues RPUSHX, see List.RPushX.
+func (l *PipeList) RPushX(elems ...interface{}) *IntResult {
+	r := &IntResult{result: pending()}
+	l.push("RPUSHX", elems, r)
+	return r
+}
+
+// push queues cmd pushing elems encoded with Pipe's codec and transformer.
+func (l *PipeList) push(cmd string, elems []interface{}, r *IntResult) {
+	elems, err := l.pipe.cyclone.encode(elems, 0, 1)
+	if err != nil {
+		l.pipe.reject(err, r.done)
+		return
+	}
+	l.pipe.queue(radix.FlatCmd(&r.val, cmd, l.key, elems...), r.done)
+}
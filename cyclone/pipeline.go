@@ -0,0 +1,180 @@
+package cyclone
+
+import (
+	"errors"
+	"io"
+
+	"github.com/mediocregopher/radix/v3"
+	"github.com/mediocregopher/radix/v3/resp"
+)
+
+// Pipe queues commands which are sent to redis in a single round trip.
+// Every queued command returns a typed result which is filled once
+// the pipe is executed.
+type Pipe struct {
+	cyclone   *Cyclone
+	cmds      []*pipeCmd
+	encodeErr error // the first error of encoding queued values
+}
+
+type pipeCmd struct {
+	action radix.CmdAction
+	finish func(error) error // fills result and returns the final error
+	err    error
+	done   bool
+}
+
+// pipeAction writes all queued commands at once and then reads their replies.
+// Unlike radix.Pipeline it does not stop on the first error reply,
+// each command receives its own error.
+type pipeAction []*pipeCmd
+
+// Pipeline queues commands issued inside fn and executes them in a single round trip.
+// Results passed back from queued commands are filled before Pipeline returns.
+// Returned error is either a connection error or the first error of queued
+// commands, replied by redis or returned when a read value cannot be restored.
+//
+//   c.Pipeline(func(p *cyclone.Pipe) {
+//     hits = p.Hash("stats").Incr("hits", 1)
+//     p.List("queue").RPush("job")
+//   })
+//
+// On radix.Cluster the batch is sent to the node serving the first key, so keys
+// of all queued commands should belong to the same node (e.g. share a hash tag
+// like "{user:1}"). Commands with keys of other nodes fail with MOVED errors.
+//
+// Values are encoded and restored with Cyclone's codec and transformer the same way
+// as by Hash and List wrappers. When any value cannot be encoded, nothing is sent
+// and the encoding error is returned.
+func (c *Cyclone) Pipeline(fn func(p *Pipe)) error {
+	p := &Pipe{cyclone: c}
+	fn(p)
+	if p.encodeErr != nil {
+		return p.encodeErr
+	}
+	if len(p.cmds) == 0 {
+		return nil
+	}
+
+	if err := c.do(pipeAction(p.cmds)); err != nil {
+		p.fail(err)
+		return err
+	}
+	return p.err()
+}
+
+// Hash returns pipelined Hash wrapper.
+func (p *Pipe) Hash(key string) *PipeHash {
+	return &PipeHash{pipe: p, key: key}
+}
+
+// List returns pipelined List wrapper.
+func (p *Pipe) List(key string) *PipeList {
+	return &PipeList{pipe: p, key: key}
+}
+
+// Len returns the number of queued commands.
+func (p *Pipe) Len() int {
+	return len(p.cmds)
+}
+
+func (p *Pipe) queue(action radix.CmdAction, finish func(error) error) {
+	p.cmds = append(p.cmds, &pipeCmd{action: action, finish: finish})
+}
+
+// reject finishes command whose values could not be encoded with err.
+// The pipe is not executed then.
+func (p *Pipe) reject(err error, finish func(error) error) {
+	if p.encodeErr == nil {
+		p.encodeErr = err
+	}
+	finish(err)
+}
+
+// restore restores value read into s with Pipe's transformer when err is nil.
+func (p *Pipe) restore(s *string, err error) error {
+	if err == nil {
+		*s, err = p.cyclone.restore(*s)
+	}
+	return err
+}
+
+// restoreAll restores values read into vals with Pipe's transformer when err is nil.
+func (p *Pipe) restoreAll(vals []string, err error) error {
+	if err == nil {
+		err = p.cyclone.restoreAll(vals)
+	}
+	return err
+}
+
+// fail finishes commands that were not executed with err.
+func (p *Pipe) fail(err error) {
+	for _, cmd := range p.cmds {
+		if !cmd.done {
+			cmd.complete(err)
+		}
+	}
+}
+
+// err returns the first error of queued commands.
+func (p *Pipe) err() error {
+	for _, cmd := range p.cmds {
+		if cmd.err != nil {
+			return cmd.err
+		}
+	}
+	return nil
+}
+
+// Keys implements radix.Action. Only the first key of the batch is returned,
+// so radix.Cluster sends the whole batch to the node serving it instead of
+// rejecting batches spanning multiple slots.
+func (p pipeAction) Keys() []string {
+	for _, cmd := range p {
+		if keys := cmd.action.Keys(); len(keys) > 0 {
+			return keys[:1]
+		}
+	}
+	return nil
+}
+
+// MarshalRESP implements resp.Marshaler.
+func (p pipeAction) MarshalRESP(w io.Writer) error {
+	for _, cmd := range p {
+		if err := cmd.action.MarshalRESP(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Run implements radix.Action.
+func (p pipeAction) Run(conn radix.Conn) error {
+	if err := conn.Encode(p); err != nil {
+		return err
+	}
+	for _, cmd := range p {
+		if err := decodeReply(conn, cmd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeReply reads reply of cmd. Error replies are passed to cmd,
+// error is returned only when the connection can no longer be used.
+func decodeReply(conn radix.Conn, cmd *pipeCmd) error {
+	err := conn.Decode(cmd.action)
+	if err != nil && !errors.As(err, new(resp.ErrDiscarded)) {
+		return err
+	}
+	cmd.complete(wrapErr(err))
+	return nil
+}
+
+// complete fills result of cmd and stores its final error, e.g. error
+// of restoring a value read by cmd.
+func (cmd *pipeCmd) complete(err error) {
+	cmd.err = cmd.finish(err)
+	cmd.done = true
+}
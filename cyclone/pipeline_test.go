@@ -0,0 +1,168 @@
+package cyclone
+
+import (
+	"crypto/cipher"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/franela/goblin"
+	"github.com/mediocregopher/radix/v3"
+)
+
+func TestPipeline(t *testing.T) {
+	g := goblin.Goblin(t)
+
+	withConn(func(c *Cyclone) {
+		g.Describe(".Pipeline", func() {
+			g.It("Executes queued commands and fills results", func() {
+				c.Raw.Do(radix.Cmd(nil, "HSET", "PipelineHash", "a", "1"))
+
+				var (
+					incr   *IntResult
+					get    *StringResult
+					exists *BoolResult
+					push   *IntResult
+					pop    *StringResult
+					all    *StringMapResult
+				)
+				err := c.Pipeline(func(p *Pipe) {
+					incr = p.Hash("PipelineHash").Incr("a", 2)
+					get = p.Hash("PipelineHash").Get("a")
+					exists = p.Hash("PipelineHash").Exists("b")
+					push = p.List("PipelineList").RPush("x", "y")
+					pop = p.List("PipelineList").Pop()
+					all = p.Hash("PipelineHash").GetAll()
+				})
+				g.Assert(err).Eql(nil)
+
+				val, err := incr.Val()
+				g.Assert(val).Eql(3)
+				g.Assert(err).Eql(nil)
+
+				str, _ := get.Val()
+				g.Assert(str).Eql("3")
+
+				found, _ := exists.Val()
+				g.Assert(found).IsFalse()
+
+				length, _ := push.Val()
+				g.Assert(length).Eql(2)
+
+				elem, _ := pop.Val()
+				g.Assert(elem).Eql("x")
+
+				fields, _ := all.Val()
+				g.Assert(fields).Eql(map[string]string{"a": "3"})
+			})
+
+			g.It("Reports errors per command", func() {
+				c.Raw.Do(radix.Cmd(nil, "HSET", "PipelineWrongType", "a", "1"))
+
+				var pop, popEmpty *StringResult
+				var length *IntResult
+				err := c.Pipeline(func(p *Pipe) {
+					pop = p.List("PipelineWrongType").Pop()
+					popEmpty = p.List("PipelineEmpty").Pop()
+					length = p.Hash("PipelineWrongType").Len()
+				})
+				g.Assert(err).Eql(ErrWrongType)
+				g.Assert(pop.Err()).Eql(ErrWrongType)
+				g.Assert(popEmpty.Err()).Eql(ErrNil)
+
+				val, err := length.Val()
+				g.Assert(val).Eql(1)
+				g.Assert(err).Eql(nil)
+			})
+
+			g.It("Routes batch spanning slots by the first key", func() {
+				cluster, err := radix.NewCluster([]string{fmt.Sprintf("%s:%s", os.Getenv("REDIS_HOST"), os.Getenv("REDIS_PORT"))})
+				g.Assert(err).Eql(nil)
+				defer cluster.Close()
+
+				var n *IntResult
+				err = New(cluster).Pipeline(func(p *Pipe) {
+					p.List("PipelineClusterA").RPush("a")
+					p.List("PipelineClusterB").RPush("b")
+					n = p.List("PipelineClusterC").RPush("c")
+				})
+				g.Assert(err).Eql(nil)
+				g.Assert(n.Err()).Eql(nil)
+
+				keys := pipeAction{
+					{action: radix.Cmd(nil, "PING")},
+					{action: radix.Cmd(nil, "GET", "a")},
+					{action: radix.Cmd(nil, "GET", "b")},
+				}.Keys()
+				g.Assert(keys).Eql([]string{"a"})
+			})
+
+			g.It("Returns ErrNotExecuted before execution", func() {
+				var r *IntResult
+				c.Pipeline(func(p *Pipe) {
+					r = p.List("PipelineNotExecuted").Len()
+					g.Assert(r.Err()).Eql(ErrNotExecuted)
+					g.Assert(p.Len()).Eql(1)
+				})
+				g.Assert(r.Err()).Eql(nil)
+			})
+
+			g.It("Encodes and restores values", func() {
+				gz := NewCompression(Gzip, CompressOptions{Threshold: 1})
+				blob := strings.Repeat("a", 100)
+
+				var vals, elems *StringsResult
+				err := c.WithCodec(JSON).WithTransformer(gz).Pipeline(func(p *Pipe) {
+					p.Hash("PipelineCodec").Set("tags", []string{blob})
+					p.List("PipelineCodecList").RPush(blob)
+					vals = p.Hash("PipelineCodec").Vals()
+					elems = p.List("PipelineCodecList").Range(0, -1)
+				})
+				g.Assert(err).Eql(nil)
+
+				v, _ := vals.Val()
+				g.Assert(v).Eql([]string{`["` + blob + `"]`})
+				e, _ := elems.Val()
+				g.Assert(e).Eql([]string{`"` + blob + `"`})
+
+				length, _ := c.Hash("PipelineCodec").StrLen("tags")
+				g.Assert(length < len(blob)).IsTrue()
+			})
+
+			g.It("Does not execute pipe when value cannot be encoded", func() {
+				var push, set *IntResult
+				err := c.WithCodec(JSON).Pipeline(func(p *Pipe) {
+					push = p.List("PipelineInvalid").RPush("a")
+					set = p.Hash("PipelineInvalid").Set("f", make(chan int))
+				})
+				g.Assert(err == nil).IsFalse()
+				g.Assert(set.Err()).Eql(err)
+				g.Assert(push.Err()).Eql(ErrNotExecuted)
+
+				length, _ := c.List("PipelineInvalid").Len()
+				g.Assert(length).Eql(0)
+			})
+
+			g.It("Returns error of restoring value", func() {
+				c.Hash("PipelineRestore").Set("f", "plain")
+				key, _ := NewAESGCM(make([]byte, 32))
+				enc, _ := NewEncryption("k", map[string]cipher.AEAD{"k": key})
+
+				var get *StringResult
+				err := c.WithTransformer(enc).Pipeline(func(p *Pipe) {
+					p.Hash("PipelineRestore").Len()
+					get = p.Hash("PipelineRestore").Get("f")
+				})
+				g.Assert(err).Eql(ErrInvalidCiphertext)
+				g.Assert(get.Err()).Eql(ErrInvalidCiphertext)
+
+				err = c.WithTransformer(enc).Tx(nil, func(tx *Tx) error {
+					tx.Pipe().Hash("PipelineRestore").Get("f")
+					return nil
+				})
+				g.Assert(err).Eql(ErrInvalidCiphertext)
+			})
+		})
+	})
+}
@@ -0,0 +1,98 @@
+package cyclone
+
+import "errors"
+
+// ErrNotExecuted is returned by results of commands which were queued
+// but not executed yet.
+var ErrNotExecuted = errors.New("cyclone: command not executed yet")
+
+// result holds error of a queued command. It is embedded by all typed results.
+type result struct {
+	err error
+}
+
+func pending() result {
+	return result{err: ErrNotExecuted}
+}
+
+// Err returns error of the command.
+func (r *result) Err() error {
+	return r.err
+}
+
+// done stores err of the command and returns it.
+func (r *result) done(err error) error {
+	r.err = err
+	return err
+}
+
+// StatusResult is a result of a queued command which replies with status only.
+type StatusResult struct {
+	result
+}
+
+// IntResult is a result of a queued command which replies with an integer.
+type IntResult struct {
+	result
+	val int
+}
+
+// Val returns reply value and error of the command.
+func (r *IntResult) Val() (int, error) {
+	return r.val, r.err
+}
+
+// FloatResult is a result of a queued command which replies with a float.
+type FloatResult struct {
+	result
+	val float64
+}
+
+// Val returns reply value and error of the command.
+func (r *FloatResult) Val() (float64, error) {
+	return r.val, r.err
+}
+
+// BoolResult is a result of a queued command which replies with a boolean.
+type BoolResult struct {
+	result
+	val bool
+}
+
+// Val returns reply value and error of the command.
+func (r *BoolResult) Val() (bool, error) {
+	return r.val, r.err
+}
+
+// StringResult is a result of a queued command which replies with a string.
+type StringResult struct {
+	result
+	val string
+}
+
+// Val returns reply value and error of the command.
+func (r *StringResult) Val() (string, error) {
+	return r.val, r.err
+}
+
+// StringsResult is a result of a queued command which replies with a list of strings.
+type StringsResult struct {
+	result
+	val []string
+}
+
+// Val returns reply value and error of the command.
+func (r *StringsResult) Val() ([]string, error) {
+	return r.val, r.err
+}
+
+// StringMapResult is a result of a queued command which replies with field/value pairs.
+type StringMapResult struct {
+	result
+	val map[string]string
+}
+
+// Val returns reply value and error of the command.
+func (r *StringMapResult) Val() (map[string]string, error) {
+	return r.val, r.err
+}
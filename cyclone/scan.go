@@ -0,0 +1,414 @@
+package cyclone
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mediocregopher/radix/v3"
+	"github.com/mediocregopher/radix/v3/resp/resp2"
+)
+
+// ScanIterator allows for channel based iteration over the keyspace.
+type ScanIterator struct {
+	scanCtl
+	cyclone *Cyclone
+	opts    radix.ScanOpts
+}
+
+// Scan iterates key names of the currently selected database.
+// https://redis.io/commands/scan
+//
+//   for hash := range c.Scan().Match("user:*").Count(500).Hashes(100) {
+//     hash.Expire(time.Hour)
+//   }
+//
+// On radix.Cluster every primary is iterated in turn and Cursor is prefixed with
+// index of the primary, e.g. "1:42". Such cursor is valid only while primaries
+// of the cluster do not change.
+//
+// Time complexity: O(1) for every call. O(N) for a complete iteration, including
+//                  enough command calls for the cursor to return back to 0.
+//                  N is the number of elements inside the collection.
+func (c *Cyclone) Scan() *ScanIterator {
+	return &ScanIterator{cyclone: c, opts: radix.ScanOpts{Command: "SCAN"}}
+}
+
+// Count sets count hint for iterator. Redis default hint is 10 when not specified.
+// https://redis.io/commands/scan#the-count-option
+//
+func (i *ScanIterator) Count(count int) *ScanIterator {
+	i.opts.Count = count
+	return i
+}
+
+// From sets cursor to start iteration from, e.g. one returned by Cursor
+// of an interrupted iteration. Elements may be returned more than once.
+func (i *ScanIterator) From(cursor string) *ScanIterator {
+	i.setCursor(cursor)
+	return i
+}
+
+// Match sets match pattern for iterator.
+// https://redis.io/commands/scan#the-match-option
+//
+func (i *ScanIterator) Match(pattern string) *ScanIterator {
+	i.opts.Pattern = pattern
+	return i
+}
+
+// Type returns only keys holding values of the given type: string, list,
+// set, zset, hash or stream (redis 6+).
+// https://redis.io/commands/scan#the-type-option
+//
+func (i *ScanIterator) Type(typ string) *ScanIterator {
+	i.opts.Type = typ
+	return i
+}
+
+// Chan returns channel and starts iteration. It will send key names.
+// Iteration stops and the channel is closed when Cyclone's context is done
+// or Stop is called, check Err after the channel is closed.
+func (i *ScanIterator) Chan(bufferSize int) <-chan string {
+	ch := make(chan string, bufferSize)
+	pager := i.pager()
+
+	go func() {
+		defer close(ch)
+		scanChan(pager, &i.scanCtl, sendStrings(ch))
+	}()
+	return ch
+}
+
+// Keys is equal to Chan, but sends generic Key wrappers.
+func (i *ScanIterator) Keys(bufferSize int) <-chan *Key {
+	ch := make(chan *Key, bufferSize)
+	i.relay("", ch, func(name string) interface{} { return i.cyclone.Key(name) })
+	return ch
+}
+
+// Hashes iterates hash keys only (TYPE hash) and sends Hash wrappers.
+func (i *ScanIterator) Hashes(bufferSize int) <-chan *Hash {
+	ch := make(chan *Hash, bufferSize)
+	i.relay("hash", ch, func(name string) interface{} { return i.cyclone.Hash(name) })
+	return ch
+}
+
+// Lists iterates list keys only (TYPE list) and sends List wrappers.
+func (i *ScanIterator) Lists(bufferSize int) <-chan *List {
+	ch := make(chan *List, bufferSize)
+	i.relay("list", ch, func(name string) interface{} { return i.cyclone.List(name) })
+	return ch
+}
+
+// Sets iterates set keys only (TYPE set) and sends Set wrappers.
+func (i *ScanIterator) Sets(bufferSize int) <-chan *Set {
+	ch := make(chan *Set, bufferSize)
+	i.relay("set", ch, func(name string) interface{} { return i.cyclone.Set(name) })
+	return ch
+}
+
+// Streams iterates stream keys only (TYPE stream) and sends Stream wrappers.
+func (i *ScanIterator) Streams(bufferSize int) <-chan *Stream {
+	ch := make(chan *Stream, bufferSize)
+	i.relay("stream", ch, func(name string) interface{} { return i.cyclone.Stream(name) })
+	return ch
+}
+
+// Strings iterates string keys only (TYPE string) and sends String wrappers.
+func (i *ScanIterator) Strings(bufferSize int) <-chan *String {
+	ch := make(chan *String, bufferSize)
+	i.relay("string", ch, func(name string) interface{} { return i.cyclone.String(name) })
+	return ch
+}
+
+// ZSets iterates sorted set keys only (TYPE zset) and sends ZSet wrappers.
+func (i *ScanIterator) ZSets(bufferSize int) <-chan *ZSet {
+	ch := make(chan *ZSet, bufferSize)
+	i.relay("zset", ch, func(name string) interface{} { return i.cyclone.ZSet(name) })
+	return ch
+}
+
+// relay starts iteration over keys of typ (any type when empty) and sends
+// wrap(name) of every key to ch, which must be a channel of wrap's result type.
+// ch is closed when iteration ends.
+func (i *ScanIterator) relay(typ string, ch interface{}, wrap func(name string) interface{}) {
+	if typ != "" {
+		i.opts.Type = typ
+	}
+	pager, out := i.pager(), reflect.ValueOf(ch)
+
+	go func() {
+		defer out.Close()
+		scanChan(pager, &i.scanCtl, func(names []string, abort, done <-chan struct{}) bool {
+			cases := []reflect.SelectCase{
+				{Dir: reflect.SelectSend, Chan: out},
+				{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(abort)},
+				{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(done)},
+			}
+			for _, name := range names {
+				cases[0].Send = reflect.ValueOf(wrap(name))
+				if chosen, _, _ := reflect.Select(cases); chosen != 0 {
+					return false
+				}
+			}
+			return true
+		})
+	}()
+}
+
+// pager returns SCAN pager with iterator options.
+func (i *ScanIterator) pager() scanPager {
+	return scanPager{cyclone: i.cyclone, opts: i.opts, cursor: i.Cursor()}
+}
+
+// scanCtl is embedded in channel based iterators. It reports error
+// which ended iteration and allows to stop iteration early.
+type scanCtl struct {
+	mu     sync.Mutex
+	abort  chan struct{} // closed when stopped or context is done
+	err    error
+	cursor string
+}
+
+// Cursor returns cursor to resume iteration from, see From. It is advanced
+// when all elements of a page were sent to the channel, so elements still
+// waiting in the channel buffer are not covered by it. Cursor "0" is returned
+// before iteration starts and after it is complete.
+func (s *scanCtl) Cursor() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cursor == "" {
+		return "0"
+	}
+	return s.cursor
+}
+
+// Err returns error which ended iteration, e.g. broken connection or context
+// error. It should be checked after the channel is closed, nil means that
+// all elements were sent or iteration was stopped by Stop.
+func (s *scanCtl) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Stop stops iteration and closes the channel. It must be called when
+// the channel is not read until it is closed (or Cyclone's context is done),
+// otherwise the iterating goroutine never exits. It is safe to call Stop
+// multiple times.
+func (s *scanCtl) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.abort == nil {
+		s.abort = make(chan struct{})
+	}
+	select {
+	case <-s.abort:
+	default:
+		close(s.abort)
+	}
+}
+
+// aborted returns channel closed when iteration is stopped or context is done.
+func (s *scanCtl) aborted() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.abort == nil {
+		s.abort = make(chan struct{})
+	}
+	return s.abort
+}
+
+// watch stops iteration with context error when ctx is done before finished is closed.
+func (s *scanCtl) watch(ctx context.Context, finished <-chan struct{}) {
+	select {
+	case <-ctx.Done():
+		s.fail(ctx.Err())
+		s.Stop()
+	case <-finished:
+	}
+}
+
+// setCursor sets current cursor.
+func (s *scanCtl) setCursor(cursor string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cursor = cursor
+}
+
+// fail records the first iteration error.
+func (s *scanCtl) fail(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err == nil {
+		s.err = err
+	}
+}
+
+// scanSend sends elements of a page to the consumer. It returns false when abort
+// or done (Cyclone's context) is closed before all elements were sent.
+type scanSend func(elems []string, abort, done <-chan struct{}) bool
+
+// scanChan iterates with pager and passes every page to send. Cursor is advanced
+// only after send returns, so it never covers elements which were not accepted
+// by the consumer's channel. Iteration error is recorded in ctl.
+func scanChan(pager scanPager, ctl *scanCtl, send scanSend) {
+	ctx := pager.cyclone.Context()
+	finished := make(chan struct{})
+	defer close(finished)
+	go ctl.watch(ctx, finished)
+
+	abort := ctl.aborted()
+	for !pager.done {
+		elems, err := pager.next()
+		if err != nil {
+			ctl.fail(err)
+			return
+		}
+		if !send(elems, abort, ctx.Done()) {
+			if err := ctx.Err(); err != nil {
+				ctl.fail(err)
+			}
+			return
+		}
+		ctl.setCursor(pager.cursor)
+	}
+}
+
+// sendStrings returns scanSend which sends elements to ch.
+func sendStrings(ch chan<- string) scanSend {
+	return func(elems []string, abort, done <-chan struct{}) bool {
+		for _, elem := range elems {
+			select {
+			case ch <- elem:
+			case <-abort:
+				return false
+			case <-done:
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// scanPager requests SCAN family command one page at a time.
+type scanPager struct {
+	cyclone *Cyclone
+	opts    radix.ScanOpts
+	cursor  string
+	done    bool
+	values  bool     // restore every second element (HSCAN values)
+	nodes   []string // cluster primaries, SCAN iterates them one by one
+}
+
+// next requests next page of elements and advances cursor.
+func (p *scanPager) next() ([]string, error) {
+	if p.cursor == "" {
+		p.cursor = "0"
+	}
+	client, node, cursor, err := p.node()
+	if err != nil || client == nil {
+		p.done = true
+		return nil, err
+	}
+
+	args := make([]string, 0, 8)
+	if p.opts.Key != "" {
+		args = append(args, p.opts.Key)
+	}
+	args = append(args, cursor)
+	if p.opts.Pattern != "" {
+		args = append(args, "MATCH", p.opts.Pattern)
+	}
+	if p.opts.Count > 0 {
+		args = append(args, "COUNT", strconv.Itoa(p.opts.Count))
+	}
+	if p.opts.Type != "" {
+		args = append(args, "TYPE", p.opts.Type)
+	}
+
+	var reply scanReply
+	if err := wrapErr(p.cyclone.bind(client).Do(radix.Cmd(&reply, p.opts.Command, args...))); err != nil {
+		return nil, err
+	}
+	if p.values {
+		for i := 1; i < len(reply.elems); i += 2 {
+			value, err := p.cyclone.restore(reply.elems[i])
+			if err != nil {
+				return nil, err
+			}
+			reply.elems[i] = value
+		}
+	}
+
+	p.cursor = reply.cursor
+	p.done = reply.cursor == "0"
+	if p.nodes != nil && p.done && node+1 < len(p.nodes) {
+		node, p.done = node+1, false
+	}
+	if p.nodes != nil && !p.done {
+		p.cursor = strconv.Itoa(node) + ":" + reply.cursor
+	}
+	return reply.elems, nil
+}
+
+// node returns client, node index and redis cursor of the current page.
+// SCAN on radix.Cluster is sent to every primary in turn and its cursor is
+// prefixed with index of the primary, e.g. "1:42". Nil client is returned
+// when cluster has no primaries.
+func (p *scanPager) node() (radix.Client, int, string, error) {
+	cluster, ok := p.cyclone.Raw.(*radix.Cluster)
+	if !ok || p.opts.Command != "SCAN" {
+		return p.cyclone.Raw, 0, p.cursor, nil
+	}
+
+	if p.nodes == nil {
+		p.nodes = []string{}
+		for _, primary := range cluster.Topo().Primaries() {
+			p.nodes = append(p.nodes, primary.Addr)
+		}
+	}
+	if len(p.nodes) == 0 {
+		return nil, 0, "", nil
+	}
+
+	node, cursor := 0, p.cursor
+	if n := strings.IndexByte(p.cursor, ':'); n >= 0 {
+		var err error
+		if node, err = strconv.Atoi(p.cursor[:n]); err != nil || node < 0 || node >= len(p.nodes) {
+			return nil, 0, "", fmt.Errorf("cyclone: invalid cluster cursor %q", p.cursor)
+		}
+		cursor = p.cursor[n+1:]
+	}
+	client, err := cluster.Client(p.nodes[node])
+	return client, node, cursor, err
+}
+
+// scanReply is a reply of SCAN, HSCAN, SSCAN and ZSCAN.
+type scanReply struct {
+	cursor string
+	elems  []string
+}
+
+func (r *scanReply) UnmarshalRESP(br *bufio.Reader) error {
+	var ah resp2.ArrayHeader
+	if err := ah.UnmarshalRESP(br); err != nil {
+		return err
+	}
+	if ah.N != 2 {
+		return errors.New("cyclone: invalid SCAN reply")
+	}
+
+	var bs resp2.BulkString
+	if err := bs.UnmarshalRESP(br); err != nil {
+		return err
+	}
+	r.cursor = bs.S
+	return resp2.Any{I: &r.elems}.UnmarshalRESP(br)
+}
@@ -0,0 +1,156 @@
+package cyclone
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/franela/goblin"
+	"github.com/mediocregopher/radix/v3"
+)
+
+func TestScan(t *testing.T) {
+	g := goblin.Goblin(t)
+
+	g.Describe(".Scan cursor", func() {
+		g.It("Resumes iteration from cursor", func() {
+			c := scanStub("SCAN", []string{"a"}, []string{"b"}, []string{"c"})
+
+			names := []string{}
+			it := c.Scan().From("1")
+			for key := range it.Keys(0) {
+				names = append(names, key.Name())
+			}
+			g.Assert(names).Eql([]string{"b", "c"})
+			g.Assert(it.Err()).Eql(nil)
+		})
+	})
+
+	sorted := func(names []string) []string {
+		sort.Strings(names)
+		return names
+	}
+
+	withConn(func(c *Cyclone) {
+		g.Describe(".Scan", func() {
+			g.BeforeEach(func() {
+				c.Hash("ScanHashA").Set("a", "1")
+				c.Hash("ScanHashB").Set("a", "1")
+				c.List("ScanList").Push("a")
+				c.Set("ScanSet").Add("a")
+				c.String("ScanString").Set("a")
+				c.ZSet("ScanZSet").Add(ZMember{"a", 1})
+				c.Stream("ScanStream").Add("a", 1)
+				c.String("OtherString").Set("a")
+			})
+
+			g.It("Iterates key names", func() {
+				names := []string{}
+				for name := range c.Scan().Match("Scan*").Count(2).Chan(10) {
+					names = append(names, name)
+				}
+				g.Assert(sorted(names)).Eql([]string{
+					"ScanHashA", "ScanHashB", "ScanList", "ScanSet", "ScanStream", "ScanString", "ScanZSet",
+				})
+			})
+
+			g.It("Filters keys by type", func() {
+				names := []string{}
+				for name := range c.Scan().Type("string").Chan(10) {
+					names = append(names, name)
+				}
+				g.Assert(sorted(names)).Eql([]string{"OtherString", "ScanString"})
+			})
+
+			g.It("Sends generic key wrappers", func() {
+				names := []string{}
+				for key := range c.Scan().Match("Scan*").Keys(10) {
+					names = append(names, key.Name())
+				}
+				g.Assert(len(names)).Eql(7)
+			})
+
+			g.It("Sends typed wrappers", func() {
+				hashes := []string{}
+				for hash := range c.Scan().Hashes(10) {
+					val, _ := hash.Get("a")
+					g.Assert(val).Eql("1")
+					hashes = append(hashes, hash.Name())
+				}
+				g.Assert(sorted(hashes)).Eql([]string{"ScanHashA", "ScanHashB"})
+
+				for list := range c.Scan().Lists(10) {
+					g.Assert(list.Name()).Eql("ScanList")
+				}
+				for set := range c.Scan().Sets(10) {
+					g.Assert(set.Name()).Eql("ScanSet")
+				}
+				for stream := range c.Scan().Streams(10) {
+					g.Assert(stream.Name()).Eql("ScanStream")
+				}
+				for zset := range c.Scan().ZSets(10) {
+					g.Assert(zset.Name()).Eql("ScanZSet")
+				}
+
+				strings := []string{}
+				for str := range c.Scan().Match("Scan*").Strings(10) {
+					strings = append(strings, str.Name())
+				}
+				g.Assert(strings).Eql([]string{"ScanString"})
+			})
+
+			g.It("Stops when context is done", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				count := 0
+				for range c.WithContext(ctx).Scan().Chan(0) {
+					count++
+				}
+				g.Assert(count).Eql(0)
+			})
+
+			g.It("Reports context error", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				it := c.WithContext(ctx).Scan()
+				for range it.Hashes(0) {
+				}
+				g.Assert(it.Err()).Eql(context.Canceled)
+			})
+
+			g.It("Iterates cluster primaries", func() {
+				cluster, err := radix.NewCluster([]string{fmt.Sprintf("%s:%s", os.Getenv("REDIS_HOST"), os.Getenv("REDIS_PORT"))})
+				g.Assert(err).Eql(nil)
+				defer cluster.Close()
+
+				names := []string{}
+				it := New(cluster).Scan().Match("Scan*").Count(2)
+				for name := range it.Chan(0) {
+					names = append(names, name)
+				}
+				g.Assert(it.Err()).Eql(nil)
+				g.Assert(len(names)).Eql(7)
+				g.Assert(it.Cursor()).Eql("0")
+
+				it = New(cluster).Scan().From("1:0")
+				for range it.Chan(0) {
+				}
+				g.Assert(it.Err() == nil).IsFalse()
+			})
+
+			g.It("Stops iteration", func() {
+				it := c.Scan().Count(1)
+				keys := it.Keys(0)
+				<-keys
+				it.Stop()
+				for range keys {
+				}
+				g.Assert(it.Err()).Eql(nil)
+			})
+		})
+	})
+}
@@ -0,0 +1,104 @@
+package cyclone
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/mediocregopher/radix/v3"
+	"github.com/mediocregopher/radix/v3/resp/resp2"
+)
+
+// Named is implemented by key wrappers (Hash, List, ...).
+// It is used to bind keys to scripts.
+type Named interface {
+	Name() string
+}
+
+// Script is a Lua script executed by its SHA1 digest.
+// Script is safe for concurrent use.
+type Script struct {
+	src string
+	sha string
+}
+
+// scriptAction is a command whose keys are the keys passed to the script,
+// so that it is routed properly by cluster clients.
+type scriptAction struct {
+	radix.CmdAction
+	keys []string
+}
+
+// NewScript creates Script from Lua source. Digest is calculated locally,
+// script is sent to redis only by Load or when EVALSHA reports it missing.
+func NewScript(src string) *Script {
+	sum := sha1.Sum([]byte(src))
+	return &Script{src: src, sha: hex.EncodeToString(sum[:])}
+}
+
+// SHA returns SHA1 digest of the script.
+func (s *Script) SHA() string {
+	return s.sha
+}
+
+// Load loads script into the scripts cache (SCRIPT LOAD).
+// Loading is optional since Run falls back to EVAL.
+// https://redis.io/commands/script-load
+//
+// Time complexity: O(N) with N being the length in bytes of the script body.
+func (s *Script) Load(c *Cyclone) error {
+	var sha string
+	if err := c.do(radix.Cmd(&sha, "SCRIPT", "LOAD", s.src)); err != nil {
+		return err
+	}
+	if sha != s.sha {
+		return errors.New("cyclone: script digest mismatch " + sha)
+	}
+	return nil
+}
+
+// Run executes script with EVALSHA, on NOSCRIPT error it falls back to EVAL
+// which also caches the script for subsequent calls. Reply is unmarshaled into rcv
+// following radix rules. Args are flattened the same way as in Hash.Set.
+// https://redis.io/commands/evalsha
+//
+//   script := cyclone.NewScript(`
+//     local job = redis.call("LPOP", KEYS[1])
+//     if job then redis.call("HINCRBY", KEYS[2], "popped", 1) end
+//     return job`)
+//   err := script.Run(c, &job, []cyclone.Named{c.List("jobs"), c.Hash("stats")})
+func (s *Script) Run(c *Cyclone, rcv interface{}, keys []Named, args ...interface{}) error {
+	err := c.do(s.cmd(rcv, "EVALSHA", s.sha, keys, args))
+	if isNoScript(err) {
+		err = c.do(s.cmd(rcv, "EVAL", s.src, keys, args))
+	}
+	return err
+}
+
+func (s *Script) cmd(rcv interface{}, cmd, script string, keys []Named, args []interface{}) radix.Action {
+	names := make([]string, len(keys))
+	flat := make([]interface{}, 0, len(keys)+len(args)+1)
+	flat = append(flat, strconv.Itoa(len(keys)))
+	for i, key := range keys {
+		names[i] = key.Name()
+		flat = append(flat, names[i])
+	}
+	flat = append(flat, args...)
+
+	return &scriptAction{
+		CmdAction: radix.FlatCmd(rcv, cmd, script, flat...),
+		keys:      names,
+	}
+}
+
+// Keys implements radix.Action.
+func (a *scriptAction) Keys() []string {
+	return a.keys
+}
+
+func isNoScript(err error) bool {
+	var respErr resp2.Error
+	return errors.As(err, &respErr) && strings.HasPrefix(respErr.Error(), "NOSCRIPT")
+}
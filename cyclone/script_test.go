@@ -0,0 +1,70 @@
+package cyclone
+
+import (
+	"testing"
+
+	"github.com/franela/goblin"
+	"github.com/mediocregopher/radix/v3"
+)
+
+func TestScript(t *testing.T) {
+	g := goblin.Goblin(t)
+
+	withConn(func(c *Cyclone) {
+		popAndCount := NewScript(`
+			local elem = redis.call("LPOP", KEYS[1])
+			if elem then
+				redis.call("HINCRBY", KEYS[2], "popped", ARGV[1])
+			end
+			return elem
+		`)
+
+		g.Describe(".Load", func() {
+			g.It("Loads script into cache", func() {
+				g.Assert(popAndCount.Load(c)).Eql(nil)
+
+				var exists []int
+				c.Raw.Do(radix.Cmd(&exists, "SCRIPT", "EXISTS", popAndCount.SHA()))
+				g.Assert(exists).Eql([]int{1})
+			})
+		})
+
+		g.Describe(".Run", func() {
+			g.It("Runs script with bound keys and args", func() {
+				list := c.List("ScriptRunList")
+				stats := c.Hash("ScriptRunStats")
+				list.RPush("a", "b")
+
+				var elem string
+				err := popAndCount.Run(c, &elem, []Named{list, stats}, 2)
+				g.Assert(err).Eql(nil)
+				g.Assert(elem).Eql("a")
+
+				popped, _ := stats.Get("popped")
+				g.Assert(popped).Eql("2")
+			})
+
+			g.It("Falls back to EVAL when script is not cached", func() {
+				c.Raw.Do(radix.Cmd(nil, "SCRIPT", "FLUSH"))
+				script := NewScript(`return redis.call("RPUSH", KEYS[1], ARGV[1], ARGV[2])`)
+
+				var length int
+				err := script.Run(c, &length, []Named{c.List("ScriptFallback")}, "x", 1)
+				g.Assert(err).Eql(nil)
+				g.Assert(length).Eql(2)
+
+				var exists []int
+				c.Raw.Do(radix.Cmd(&exists, "SCRIPT", "EXISTS", script.SHA()))
+				g.Assert(exists).Eql([]int{1})
+			})
+
+			g.It("Returns script errors", func() {
+				c.Raw.Do(radix.Cmd(nil, "HSET", "ScriptWrongType", "a", "1"))
+				script := NewScript(`return redis.call("LPOP", KEYS[1])`)
+
+				err := script.Run(c, nil, []Named{c.Hash("ScriptWrongType")})
+				g.Assert(err == nil).IsFalse()
+			})
+		})
+	})
+}
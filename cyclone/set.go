@@ -0,0 +1,288 @@
+package cyclone
+
+import (
+	"strconv"
+
+	"github.com/mediocregopher/radix/v3"
+)
+
+// Set wraps redis set operations.
+type Set struct {
+	Key
+}
+
+// SetScanIterator allows for channel based iteration.
+type SetScanIterator struct {
+	scanCtl
+	set  *Set
+	opts radix.ScanOpts
+}
+
+// Add adds the specified members to the set stored at key. Specified members
+// that are already a member of this set are ignored. If key does not exist,
+// a new set is created before adding the specified members.
+// https://redis.io/commands/sadd
+//
+// Time complexity: O(1) for each element added, so O(N) to add N elements
+//                  when the command is called with multiple arguments.
+func (s *Set) Add(members ...interface{}) (addedMembers int, err error) {
+	err = s.cyclone.do(radix.FlatCmd(&addedMembers, "SADD", s.key, members...))
+	return
+}
+
+// Card returns the set cardinality (number of elements) of the set stored at key.
+// https://redis.io/commands/scard
+//
+// Time complexity: O(1)
+func (s *Set) Card() (card int, err error) {
+	err = s.cyclone.do(radix.Cmd(&card, "SCARD", s.key))
+	return
+}
+
+// Diff returns the members of the set resulting from the difference between
+// this set and all the successive sets.
+// https://redis.io/commands/sdiff
+//
+// Time complexity: O(N) where N is the total number of elements in all given sets.
+func (s *Set) Diff(others ...*Set) (members []string, err error) {
+	err = s.cyclone.do(radix.Cmd(&members, "SDIFF", s.keys(others)...))
+	return
+}
+
+// DiffStore is equal to Diff, but instead of returning the resulting set,
+// it is stored in dst. Returns the number of elements in the resulting set.
+// https://redis.io/commands/sdiffstore
+//
+// Time complexity: O(N) where N is the total number of elements in all given sets.
+func (s *Set) DiffStore(dst *Set, others ...*Set) (card int, err error) {
+	err = s.cyclone.do(radix.Cmd(&card, "SDIFFSTORE", dst.storeKeys(s, others)...))
+	return
+}
+
+// Inter returns the members of the set resulting from the intersection
+// of this set and all the given sets.
+// https://redis.io/commands/sinter
+//
+// Time complexity: O(N*M) worst case where N is the cardinality of the smallest
+//                  set and M is the number of sets.
+func (s *Set) Inter(others ...*Set) (members []string, err error) {
+	err = s.cyclone.do(radix.Cmd(&members, "SINTER", s.keys(others)...))
+	return
+}
+
+// InterCard returns the cardinality of the set which would result from
+// the intersection of this set and all the given sets. Limit of zero means
+// unlimited, otherwise computation stops when limit is reached.
+// https://redis.io/commands/sintercard
+//
+// Time complexity: O(N*M) worst case where N is the cardinality of the smallest
+//                  set and M is the number of sets.
+func (s *Set) InterCard(limit int, others ...*Set) (card int, err error) {
+	keys := s.keys(others)
+	args := make([]string, 0, len(keys)+3)
+	args = append(args, strconv.Itoa(len(keys)))
+	args = append(args, keys...)
+	if limit > 0 {
+		args = append(args, "LIMIT", strconv.Itoa(limit))
+	}
+	err = s.cyclone.do(radix.Cmd(&card, "SINTERCARD", args...))
+	return
+}
+
+// InterStore is equal to Inter, but instead of returning the resulting set,
+// it is stored in dst. Returns the number of elements in the resulting set.
+// https://redis.io/commands/sinterstore
+//
+// Time complexity: O(N*M) worst case where N is the cardinality of the smallest
+//                  set and M is the number of sets.
+func (s *Set) InterStore(dst *Set, others ...*Set) (card int, err error) {
+	err = s.cyclone.do(radix.Cmd(&card, "SINTERSTORE", dst.storeKeys(s, others)...))
+	return
+}
+
+// IsMember returns if member is a member of the set stored at key.
+// https://redis.io/commands/sismember
+//
+// Time complexity: O(1)
+func (s *Set) IsMember(member string) (bool, error) {
+	var isMember int
+	err := s.cyclone.do(radix.Cmd(&isMember, "SISMEMBER", s.key, member))
+	return isMember == 1, err
+}
+
+// MIsMember returns whether each member is a member of the set stored at key.
+// https://redis.io/commands/smismember
+//
+// Time complexity: O(N) where N is the number of elements being checked for membership
+func (s *Set) MIsMember(members ...string) (isMember []bool, err error) {
+	var reply []int
+	args := append([]string{s.key}, members...)
+	if err = s.cyclone.do(radix.Cmd(&reply, "SMISMEMBER", args...)); err != nil {
+		return
+	}
+	isMember = make([]bool, len(reply))
+	for i, v := range reply {
+		isMember[i] = v == 1
+	}
+	return
+}
+
+// Members returns all the members of the set value stored at key.
+// https://redis.io/commands/smembers
+//
+// Time complexity: O(N) where N is the set cardinality.
+func (s *Set) Members() (members []string, err error) {
+	err = s.cyclone.do(radix.Cmd(&members, "SMEMBERS", s.key))
+	return
+}
+
+// Move moves member from this set to dst. This operation is atomic.
+// Returns false when member was not a member of this set.
+// https://redis.io/commands/smove
+//
+// Time complexity: O(1)
+func (s *Set) Move(dst *Set, member string) (bool, error) {
+	var moved int
+	err := s.cyclone.do(radix.Cmd(&moved, "SMOVE", s.key, dst.key, member))
+	return moved == 1, err
+}
+
+// Pop removes and returns a random member from the set stored at key.
+// ErrNil is returned when the set is empty.
+// https://redis.io/commands/spop
+//
+// Time complexity: O(1)
+func (s *Set) Pop() (member string, err error) {
+	mn := radix.MaybeNil{Rcv: &member}
+	err = s.cyclone.do(radix.Cmd(&mn, "SPOP", s.key))
+	err = nilErr(&mn, err)
+	return
+}
+
+// PopN removes and returns up to count random members from the set stored at key.
+// https://redis.io/commands/spop
+//
+// Time complexity: O(N) where N is the value of the passed count.
+func (s *Set) PopN(count int) (members []string, err error) {
+	err = s.cyclone.do(radix.Cmd(&members, "SPOP", s.key, strconv.Itoa(count)))
+	return
+}
+
+// RandMember returns a random member from the set stored at key.
+// ErrNil is returned when the set is empty.
+// https://redis.io/commands/srandmember
+//
+// Time complexity: O(1)
+func (s *Set) RandMember() (member string, err error) {
+	mn := radix.MaybeNil{Rcv: &member}
+	err = s.cyclone.do(radix.Cmd(&mn, "SRANDMEMBER", s.key))
+	err = nilErr(&mn, err)
+	return
+}
+
+// RandMembers returns up to count distinct random members from the set stored at key.
+// Negative count allows the same member to be returned multiple times,
+// in this case exactly abs(count) members are returned.
+// https://redis.io/commands/srandmember
+//
+// Time complexity: O(N) where N is the absolute value of the passed count.
+func (s *Set) RandMembers(count int) (members []string, err error) {
+	err = s.cyclone.do(radix.Cmd(&members, "SRANDMEMBER", s.key, strconv.Itoa(count)))
+	return
+}
+
+// Rem removes the specified members from the set stored at key. Specified members
+// that are not a member of this set are ignored.
+// https://redis.io/commands/srem
+//
+// Time complexity: O(N) where N is the number of members to be removed.
+func (s *Set) Rem(members ...interface{}) (removedMembers int, err error) {
+	err = s.cyclone.do(radix.FlatCmd(&removedMembers, "SREM", s.key, members...))
+	return
+}
+
+// Scan iterates elements of Set types.
+// https://redis.io/commands/sscan
+// https://redis.io/commands/scan
+//
+// Time complexity: O(1) for every call. O(N) for a complete iteration, including
+//                  enough command calls for the cursor to return back to 0.
+//                  N is the number of elements inside the collection.
+func (s *Set) Scan() *SetScanIterator {
+	return &SetScanIterator{set: s}
+}
+
+// Union returns the members of the set resulting from the union of this set
+// and all the given sets.
+// https://redis.io/commands/sunion
+//
+// Time complexity: O(N) where N is the total number of elements in all given sets.
+func (s *Set) Union(others ...*Set) (members []string, err error) {
+	err = s.cyclone.do(radix.Cmd(&members, "SUNION", s.keys(others)...))
+	return
+}
+
+// UnionStore is equal to Union, but instead of returning the resulting set,
+// it is stored in dst. Returns the number of elements in the resulting set.
+// https://redis.io/commands/sunionstore
+//
+// Time complexity: O(N) where N is the total number of elements in all given sets.
+func (s *Set) UnionStore(dst *Set, others ...*Set) (card int, err error) {
+	err = s.cyclone.do(radix.Cmd(&card, "SUNIONSTORE", dst.storeKeys(s, others)...))
+	return
+}
+
+// keys returns key of this set followed by keys of others.
+func (s *Set) keys(others []*Set) []string {
+	keys := make([]string, 0, len(others)+1)
+	keys = append(keys, s.key)
+	for _, other := range others {
+		keys = append(keys, other.key)
+	}
+	return keys
+}
+
+// storeKeys returns destination key followed by source keys.
+func (s *Set) storeKeys(src *Set, others []*Set) []string {
+	return append([]string{s.key}, src.keys(others)...)
+}
+
+// Count sets count hint for iterator. Redis default hint is 10 when not specified.
+// https://redis.io/commands/scan#the-count-option
+//
+func (i *SetScanIterator) Count(count int) *SetScanIterator {
+	i.opts.Count = count
+	return i
+}
+
+// Match sets match pattern for iterator.
+// https://redis.io/commands/scan#the-match-option
+//
+func (i *SetScanIterator) Match(pattern string) *SetScanIterator {
+	i.opts.Pattern = pattern
+	return i
+}
+
+// From sets cursor to start iteration from, e.g. one returned by Cursor
+// of an interrupted iteration. Elements may be returned more than once.
+func (i *SetScanIterator) From(cursor string) *SetScanIterator {
+	i.setCursor(cursor)
+	return i
+}
+
+// Chan returns channel and starts iteration. Iteration stops and the channel
+// is closed when Cyclone's context is done or Stop is called, check Err after
+// the channel is closed.
+func (i *SetScanIterator) Chan(bufferSize int) <-chan string {
+	ch := make(chan string, bufferSize)
+
+	i.opts.Command = "SSCAN"
+	i.opts.Key = i.set.key
+	pager := scanPager{cyclone: i.set.cyclone, opts: i.opts, cursor: i.Cursor()}
+
+	go func() {
+		defer close(ch)
+		scanChan(pager, &i.scanCtl, sendStrings(ch))
+	}()
+	return ch
+}
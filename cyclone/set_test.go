@@ -0,0 +1,207 @@
+package cyclone
+
+import (
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/franela/goblin"
+)
+
+func TestSet(t *testing.T) {
+	g := goblin.Goblin(t)
+
+	sorted := func(members []string, err error) []string {
+		sort.Strings(members)
+		return members
+	}
+
+	withConn(func(c *Cyclone) {
+		g.Describe(".Add", func() {
+			g.It("Adds members and returns added count", func() {
+				added, _ := c.Set("SetAdd").Add("a", "b", "a")
+				g.Assert(added).Eql(2)
+
+				added, _ = c.Set("SetAdd").Add("b", "c")
+				g.Assert(added).Eql(1)
+
+				g.Assert(sorted(c.Set("SetAdd").Members())).Eql([]string{"a", "b", "c"})
+			})
+		})
+
+		g.Describe(".Card", func() {
+			g.It("Returns number of members", func() {
+				c.Set("SetCard").Add("a", "b")
+
+				card, _ := c.Set("SetCard").Card()
+				g.Assert(card).Eql(2)
+			})
+		})
+
+		g.Describe(".Diff", func() {
+			g.It("Returns and stores difference", func() {
+				c.Set("SetDiffA").Add("a", "b", "c")
+				c.Set("SetDiffB").Add("c", "d")
+
+				g.Assert(sorted(c.Set("SetDiffA").Diff(c.Set("SetDiffB")))).Eql([]string{"a", "b"})
+
+				card, _ := c.Set("SetDiffA").DiffStore(c.Set("SetDiffDst"), c.Set("SetDiffB"))
+				g.Assert(card).Eql(2)
+				g.Assert(sorted(c.Set("SetDiffDst").Members())).Eql([]string{"a", "b"})
+			})
+		})
+
+		g.Describe(".Inter", func() {
+			g.It("Returns and stores intersection", func() {
+				c.Set("SetInterA").Add("a", "b", "c")
+				c.Set("SetInterB").Add("b", "c", "d")
+
+				g.Assert(sorted(c.Set("SetInterA").Inter(c.Set("SetInterB")))).Eql([]string{"b", "c"})
+
+				card, _ := c.Set("SetInterA").InterStore(c.Set("SetInterDst"), c.Set("SetInterB"))
+				g.Assert(card).Eql(2)
+				g.Assert(sorted(c.Set("SetInterDst").Members())).Eql([]string{"b", "c"})
+
+				card, _ = c.Set("SetInterA").InterCard(0, c.Set("SetInterB"))
+				g.Assert(card).Eql(2)
+
+				card, _ = c.Set("SetInterA").InterCard(1, c.Set("SetInterB"))
+				g.Assert(card).Eql(1)
+			})
+		})
+
+		g.Describe(".IsMember", func() {
+			g.It("Checks membership", func() {
+				c.Set("SetIsMember").Add("a", "b")
+
+				isMember, _ := c.Set("SetIsMember").IsMember("a")
+				g.Assert(isMember).IsTrue()
+				isMember, _ = c.Set("SetIsMember").IsMember("c")
+				g.Assert(isMember).IsFalse()
+
+				members, _ := c.Set("SetIsMember").MIsMember("a", "c", "b")
+				g.Assert(members).Eql([]bool{true, false, true})
+			})
+		})
+
+		g.Describe(".Move", func() {
+			g.It("Moves member between sets", func() {
+				c.Set("SetMoveSrc").Add("a", "b")
+
+				moved, _ := c.Set("SetMoveSrc").Move(c.Set("SetMoveDst"), "a")
+				g.Assert(moved).IsTrue()
+				moved, _ = c.Set("SetMoveSrc").Move(c.Set("SetMoveDst"), "x")
+				g.Assert(moved).IsFalse()
+
+				g.Assert(sorted(c.Set("SetMoveSrc").Members())).Eql([]string{"b"})
+				g.Assert(sorted(c.Set("SetMoveDst").Members())).Eql([]string{"a"})
+			})
+		})
+
+		g.Describe(".Pop", func() {
+			g.It("Pops random members", func() {
+				_, err := c.Set("SetPop").Pop()
+				g.Assert(err).Eql(ErrNil)
+
+				c.Set("SetPop").Add("a", "b", "c")
+				member, err := c.Set("SetPop").Pop()
+				g.Assert(err).Eql(nil)
+				g.Assert(member != "").IsTrue()
+
+				members, _ := c.Set("SetPop").PopN(5)
+				g.Assert(len(members)).Eql(2)
+
+				card, _ := c.Set("SetPop").Card()
+				g.Assert(card).Eql(0)
+			})
+		})
+
+		g.Describe(".RandMember", func() {
+			g.It("Returns random members without removing them", func() {
+				_, err := c.Set("SetRandMember").RandMember()
+				g.Assert(err).Eql(ErrNil)
+
+				c.Set("SetRandMember").Add("a", "b", "c")
+				member, _ := c.Set("SetRandMember").RandMember()
+				g.Assert(member != "").IsTrue()
+
+				members, _ := c.Set("SetRandMember").RandMembers(2)
+				g.Assert(len(members)).Eql(2)
+
+				members, _ = c.Set("SetRandMember").RandMembers(-5)
+				g.Assert(len(members)).Eql(5)
+
+				card, _ := c.Set("SetRandMember").Card()
+				g.Assert(card).Eql(3)
+			})
+		})
+
+		g.Describe(".Rem", func() {
+			g.It("Removes members", func() {
+				c.Set("SetRem").Add("a", "b", "c")
+
+				removed, _ := c.Set("SetRem").Rem("a", "c", "x")
+				g.Assert(removed).Eql(2)
+				g.Assert(sorted(c.Set("SetRem").Members())).Eql([]string{"b"})
+			})
+		})
+
+		g.Describe(".Scan", func() {
+			g.It("Chan iteration", func() {
+				for i := 0; i < 100; i++ {
+					c.Set("SetScan").Add(strconv.Itoa(i))
+				}
+
+				result := make([]string, 0)
+				for member := range c.Set("SetScan").Scan().Count(20).Chan(0) {
+					result = append(result, member)
+				}
+				g.Assert(len(result)).Eql(100)
+			})
+
+			g.It("Chan Match iteration", func() {
+				for i := 0; i < 100; i++ {
+					c.Set("SetScanMatch").Add(strconv.Itoa(i))
+				}
+
+				result := make([]string, 0)
+				for member := range c.Set("SetScanMatch").Scan().Match("2*").Chan(0) {
+					result = append(result, member)
+				}
+				sort.Strings(result)
+				g.Assert(result).Eql([]string{"2", "20", "21", "22", "23", "24", "25", "26", "27", "28", "29"})
+			})
+
+			g.It("Stops iteration", func() {
+				for i := 0; i < 100; i++ {
+					c.Set("SetScanStop").Add(strconv.Itoa(i))
+				}
+
+				it := c.Set("SetScanStop").Scan().Count(10)
+				ch := it.Chan(0)
+				<-ch
+				it.Stop()
+
+				count := 0
+				for range ch {
+					count++
+				}
+				g.Assert(count < 99).IsTrue()
+				g.Assert(it.Err()).Eql(nil)
+			})
+		})
+
+		g.Describe(".Union", func() {
+			g.It("Returns and stores union", func() {
+				c.Set("SetUnionA").Add("a", "b")
+				c.Set("SetUnionB").Add("b", "c")
+
+				g.Assert(sorted(c.Set("SetUnionA").Union(c.Set("SetUnionB")))).Eql([]string{"a", "b", "c"})
+
+				card, _ := c.Set("SetUnionA").UnionStore(c.Set("SetUnionDst"), c.Set("SetUnionB"))
+				g.Assert(card).Eql(3)
+				g.Assert(sorted(c.Set("SetUnionDst").Members())).Eql([]string{"a", "b", "c"})
+			})
+		})
+	})
+}
@@ -0,0 +1,597 @@
+package cyclone
+
+import (
+	"bufio"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/mediocregopher/radix/v3"
+	"github.com/mediocregopher/radix/v3/resp/resp2"
+)
+
+// Stream wraps redis stream operations.
+type Stream struct {
+	Key
+}
+
+// StreamEntry is an entry of a stream as returned by XRANGE, XREAD, XCLAIM, etc.
+// Fields are nil when the entry was deleted while still pending.
+type StreamEntry struct {
+	ID     string
+	Fields map[string]string
+}
+
+// StreamGroup wraps operations of a stream consumer group.
+type StreamGroup struct {
+	stream *Stream
+	name   string
+}
+
+// StreamPending is an entry pending in a consumer group, as returned by XPENDING.
+type StreamPending struct {
+	ID         string
+	Consumer   string
+	Idle       time.Duration
+	Deliveries int
+}
+
+// StreamPendingSummary is the summary form of XPENDING.
+type StreamPendingSummary struct {
+	Count     int
+	Lowest    string
+	Highest   string
+	Consumers map[string]int
+}
+
+// StreamAdd is a XADD command builder.
+type StreamAdd struct {
+	stream     *Stream
+	kvpairs    []interface{}
+	id         string
+	noMkStream bool
+	trim       streamTrim
+}
+
+// StreamTrim is a XTRIM command builder.
+type StreamTrim struct {
+	stream *Stream
+	trim   streamTrim
+}
+
+// StreamRead is a XREAD/XREADGROUP command builder.
+type StreamRead struct {
+	stream   *Stream
+	group    *StreamGroup
+	consumer string
+	id       string
+	args     []string
+	block    bool
+	timeout  time.Duration
+}
+
+type streamTrim struct {
+	strategy  string
+	threshold string
+	approx    bool
+	limit     int
+}
+
+// Add appends a new entry with the given field/value pairs (or a map) to the
+// stream, ID of the entry is generated by redis. If key does not exist,
+// the stream is created. Use AddWith for options.
+// https://redis.io/commands/xadd
+//
+// Time complexity: O(1) when adding a new entry.
+func (s *Stream) Add(kvpairs ...interface{}) (id string, err error) {
+	return s.AddWith(kvpairs...).Do()
+}
+
+// AddWith returns XADD command builder with ID/NOMKSTREAM/MAXLEN/MINID options.
+// https://redis.io/commands/xadd
+//
+//   id, err := c.Stream("events").AddWith("type", "click").MaxLen(1000).Approx().Do()
+func (s *Stream) AddWith(kvpairs ...interface{}) *StreamAdd {
+	return &StreamAdd{stream: s, kvpairs: kvpairs, id: "*"}
+}
+
+// Del removes the specified entries from the stream and returns the number
+// of entries deleted. Use Key.Del to remove the whole stream.
+// https://redis.io/commands/xdel
+//
+// Time complexity: O(1) for each single item to delete in the stream.
+func (s *Stream) Del(ids ...string) (deletedEntries int, err error) {
+	args := append([]string{s.key}, ids...)
+	err = s.cyclone.do(radix.Cmd(&deletedEntries, "XDEL", args...))
+	return
+}
+
+// Group returns consumer group wrapper.
+func (s *Stream) Group(name string) *StreamGroup {
+	return &StreamGroup{stream: s, name: name}
+}
+
+// Len returns the number of entries inside the stream.
+// https://redis.io/commands/xlen
+//
+// Time complexity: O(1)
+func (s *Stream) Len() (length int, err error) {
+	err = s.cyclone.do(radix.Cmd(&length, "XLEN", s.key))
+	return
+}
+
+// Range returns entries with IDs between start and end (both inclusive).
+// "-" and "+" are the minimum and maximum possible IDs. Prefix ID with "("
+// for an exclusive bound. Count of zero returns all the entries.
+// https://redis.io/commands/xrange
+//
+// Time complexity: O(N) with N being the number of elements being returned.
+func (s *Stream) Range(start, end string, count int) ([]StreamEntry, error) {
+	return s.xrange("XRANGE", start, end, count)
+}
+
+// Read returns XREAD command builder reading entries with ID greater than id.
+// Use "$" as id to receive only entries added after blocking started.
+// https://redis.io/commands/xread
+//
+//   entries, err := c.Stream("events").Read(lastID).Count(100).Block(5 * time.Second).Do()
+func (s *Stream) Read(id string) *StreamRead {
+	return &StreamRead{stream: s, id: id}
+}
+
+// RevRange is equal to Range, but returns entries in reverse order,
+// so end bound is given first.
+// https://redis.io/commands/xrevrange
+//
+// Time complexity: O(N) with N being the number of elements being returned.
+func (s *Stream) RevRange(end, start string, count int) ([]StreamEntry, error) {
+	return s.xrange("XREVRANGE", end, start, count)
+}
+
+// Trim returns XTRIM command builder.
+// https://redis.io/commands/xtrim
+//
+//   trimmed, err := c.Stream("events").Trim().MaxLen(1000).Approx().Do()
+//
+// Time complexity: O(N), with N being the number of evicted entries.
+func (s *Stream) Trim() *StreamTrim {
+	return &StreamTrim{stream: s}
+}
+
+func (s *Stream) xrange(cmd, start, end string, count int) (entries []StreamEntry, err error) {
+	args := []string{s.key, start, end}
+	if count > 0 {
+		args = append(args, "COUNT", strconv.Itoa(count))
+	}
+	err = s.cyclone.do(radix.Cmd(&entries, cmd, args...))
+	return
+}
+
+// Ack removes the specified entries from the pending entries list of the group
+// and returns the number of acknowledged entries.
+// https://redis.io/commands/xack
+//
+// Time complexity: O(1) for each entry ID processed.
+func (g *StreamGroup) Ack(ids ...string) (acked int, err error) {
+	args := append([]string{g.stream.key, g.name}, ids...)
+	err = g.stream.cyclone.do(radix.Cmd(&acked, "XACK", args...))
+	return
+}
+
+// AutoClaim transfers ownership of up to count pending entries idle for at least
+// minIdle to consumer, scanning from start ("0-0" to scan from the beginning).
+// Returned next ID should be used as start of the following call,
+// "0-0" means the scan is complete.
+// https://redis.io/commands/xautoclaim
+//
+// Time complexity: O(1) if count is small.
+func (g *StreamGroup) AutoClaim(consumer string, minIdle time.Duration, start string, count int) (next string, entries []StreamEntry, err error) {
+	var reply autoClaimReply
+	err = g.stream.cyclone.do(radix.Cmd(
+		&reply,
+		"XAUTOCLAIM",
+		g.stream.key,
+		g.name,
+		consumer,
+		formatMs(minIdle),
+		start,
+		"COUNT",
+		strconv.Itoa(count),
+	))
+	return reply.next, reply.entries, err
+}
+
+// Claim transfers ownership of the given pending entries idle for at least
+// minIdle to consumer. Claimed entries are returned.
+// https://redis.io/commands/xclaim
+//
+// Time complexity: O(log N) with N being the number of messages in the PEL
+//                  of the consumer group.
+func (g *StreamGroup) Claim(consumer string, minIdle time.Duration, ids ...string) (entries []StreamEntry, err error) {
+	args := append([]string{g.stream.key, g.name, consumer, formatMs(minIdle)}, ids...)
+	err = g.stream.cyclone.do(radix.Cmd(&entries, "XCLAIM", args...))
+	return
+}
+
+// Create creates the consumer group, id is the last delivered ID of the group
+// ("$" for new entries only, "0" for the entire stream). Missing stream
+// is created when mkStream is true, otherwise an error is returned.
+// ErrBusyGroup is returned when the group already exists.
+// https://redis.io/commands/xgroup-create
+//
+// Time complexity: O(1)
+func (g *StreamGroup) Create(id string, mkStream bool) error {
+	args := []string{"CREATE", g.stream.key, g.name, id}
+	if mkStream {
+		args = append(args, "MKSTREAM")
+	}
+	return g.stream.cyclone.do(radix.Cmd(nil, "XGROUP", args...))
+}
+
+// Destroy destroys the consumer group, even if there are active consumers
+// and pending entries. Returns false when the group did not exist.
+// https://redis.io/commands/xgroup-destroy
+//
+// Time complexity: O(N) where N is the number of entries in the group's
+//                  pending entries list (PEL).
+func (g *StreamGroup) Destroy() (bool, error) {
+	var destroyed int
+	err := g.stream.cyclone.do(radix.Cmd(&destroyed, "XGROUP", "DESTROY", g.stream.key, g.name))
+	return destroyed == 1, err
+}
+
+// Name returns name of the consumer group.
+func (g *StreamGroup) Name() string {
+	return g.name
+}
+
+// Pending returns up to count entries with IDs between start and end
+// (see Range) from the pending entries list of the group.
+// https://redis.io/commands/xpending
+//
+// Time complexity: O(N) with N being the number of elements returned.
+func (g *StreamGroup) Pending(start, end string, count int) ([]StreamPending, error) {
+	var reply [][]interface{}
+	err := g.stream.cyclone.do(radix.Cmd(
+		&reply,
+		"XPENDING",
+		g.stream.key,
+		g.name,
+		start,
+		end,
+		strconv.Itoa(count),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	pending := make([]StreamPending, 0, len(reply))
+	for _, r := range reply {
+		if len(r) != 4 {
+			return nil, errors.New("cyclone: invalid XPENDING reply")
+		}
+		pending = append(pending, StreamPending{
+			ID:         replyString(r[0]),
+			Consumer:   replyString(r[1]),
+			Idle:       time.Duration(replyInt(r[2])) * time.Millisecond,
+			Deliveries: replyInt(r[3]),
+		})
+	}
+	return pending, nil
+}
+
+// PendingSummary returns the number of pending entries of the group,
+// their lowest and highest IDs and the number of pending entries per consumer.
+// https://redis.io/commands/xpending
+//
+// Time complexity: O(N) with N being the number of consumers.
+func (g *StreamGroup) PendingSummary() (summary StreamPendingSummary, err error) {
+	var reply []interface{}
+	if err = g.stream.cyclone.do(radix.Cmd(&reply, "XPENDING", g.stream.key, g.name)); err != nil {
+		return
+	}
+	if len(reply) != 4 {
+		return summary, errors.New("cyclone: invalid XPENDING reply")
+	}
+
+	summary.Count = replyInt(reply[0])
+	summary.Lowest = replyString(reply[1])
+	summary.Highest = replyString(reply[2])
+	summary.Consumers = map[string]int{}
+	consumers, _ := reply[3].([]interface{})
+	for _, c := range consumers {
+		if pair, ok := c.([]interface{}); ok && len(pair) == 2 {
+			summary.Consumers[replyString(pair[0])] = replyInt(pair[1])
+		}
+	}
+	return
+}
+
+// Read returns XREADGROUP command builder reading entries as consumer.
+// Use ">" as id to receive entries never delivered to other consumers,
+// any other ID returns pending entries of the consumer.
+// https://redis.io/commands/xreadgroup
+//
+//   entries, err := c.Stream("events").Group("workers").Read("w1", ">").Count(10).Do()
+func (g *StreamGroup) Read(consumer, id string) *StreamRead {
+	return &StreamRead{stream: g.stream, group: g, consumer: consumer, id: id}
+}
+
+// ID sets ID of the new entry. Defaults to "*" which auto-generates it.
+func (a *StreamAdd) ID(id string) *StreamAdd {
+	a.id = id
+	return a
+}
+
+// NoMkStream does not create the stream when it does not exist.
+func (a *StreamAdd) NoMkStream() *StreamAdd {
+	a.noMkStream = true
+	return a
+}
+
+// MaxLen trims the stream to maxLen entries.
+func (a *StreamAdd) MaxLen(maxLen int) *StreamAdd {
+	a.trim.maxLen(maxLen)
+	return a
+}
+
+// MinID evicts entries with IDs lower than id.
+func (a *StreamAdd) MinID(id string) *StreamAdd {
+	a.trim.minID(id)
+	return a
+}
+
+// Approx makes trimming almost exact (~), which is more efficient.
+func (a *StreamAdd) Approx() *StreamAdd {
+	a.trim.approx = true
+	return a
+}
+
+// Limit limits the number of entries evicted by approximate trimming.
+func (a *StreamAdd) Limit(limit int) *StreamAdd {
+	a.trim.limit = limit
+	return a
+}
+
+// Do executes XADD and returns ID of the added entry. ErrNil is returned
+// when the stream does not exist and NoMkStream was used.
+func (a *StreamAdd) Do() (id string, err error) {
+	args := []interface{}{}
+	if a.noMkStream {
+		args = append(args, "NOMKSTREAM")
+	}
+	for _, arg := range a.trim.args() {
+		args = append(args, arg)
+	}
+	args = append(args, a.id)
+	args = append(args, a.kvpairs...)
+
+	mn := radix.MaybeNil{Rcv: &id}
+	err = a.stream.cyclone.do(radix.FlatCmd(&mn, "XADD", a.stream.key, args...))
+	err = nilErr(&mn, err)
+	return
+}
+
+// MaxLen trims the stream to maxLen entries.
+func (t *StreamTrim) MaxLen(maxLen int) *StreamTrim {
+	t.trim.maxLen(maxLen)
+	return t
+}
+
+// MinID evicts entries with IDs lower than id.
+func (t *StreamTrim) MinID(id string) *StreamTrim {
+	t.trim.minID(id)
+	return t
+}
+
+// Approx makes trimming almost exact (~), which is more efficient.
+func (t *StreamTrim) Approx() *StreamTrim {
+	t.trim.approx = true
+	return t
+}
+
+// Limit limits the number of entries evicted by approximate trimming.
+func (t *StreamTrim) Limit(limit int) *StreamTrim {
+	t.trim.limit = limit
+	return t
+}
+
+// Do executes XTRIM and returns the number of evicted entries.
+func (t *StreamTrim) Do() (trimmed int, err error) {
+	if t.trim.strategy == "" {
+		return 0, errors.New("cyclone: XTRIM requires MaxLen or MinID")
+	}
+	args := append([]string{t.stream.key}, t.trim.args()...)
+	err = t.stream.cyclone.do(radix.Cmd(&trimmed, "XTRIM", args...))
+	return
+}
+
+// Count limits the number of returned entries.
+func (r *StreamRead) Count(count int) *StreamRead {
+	r.args = append(r.args, "COUNT", strconv.Itoa(count))
+	return r
+}
+
+// Block waits up to timeout for entries to arrive when there are none.
+// A timeout of zero can be used to block indefinitely. Blocking reads use
+// dedicated connections (see Connect).
+func (r *StreamRead) Block(timeout time.Duration) *StreamRead {
+	r.block, r.timeout = true, timeout
+	return r
+}
+
+// NoAck does not add read entries to the pending entries list,
+// so they do not need to be acknowledged. Applicable only to group reads.
+func (r *StreamRead) NoAck() *StreamRead {
+	r.args = append(r.args, "NOACK")
+	return r
+}
+
+// Do executes XREAD (or XREADGROUP) and returns read entries.
+// No entries and nil error are returned when there is nothing to read
+// or blocking timed out.
+func (r *StreamRead) Do() ([]StreamEntry, error) {
+	cmd := "XREAD"
+	var args []string
+	if r.group != nil {
+		cmd = "XREADGROUP"
+		args = append(args, "GROUP", r.group.name, r.consumer)
+	}
+	args = append(args, r.args...)
+	if r.block {
+		args = append(args, "BLOCK", formatMs(r.timeout))
+	}
+	args = append(args, "STREAMS", r.stream.key, r.id)
+
+	var reply []streamReply
+	mn := radix.MaybeNil{Rcv: &reply}
+	action := radix.Cmd(&mn, cmd, args...)
+
+	var err error
+	if r.block {
+		err = r.stream.cyclone.doBlocking(action)
+	} else {
+		err = r.stream.cyclone.do(action)
+	}
+	if err != nil || len(reply) == 0 {
+		return nil, err
+	}
+	return reply[0].entries, nil
+}
+
+func (t *streamTrim) maxLen(maxLen int) {
+	t.strategy, t.threshold = "MAXLEN", strconv.Itoa(maxLen)
+}
+
+func (t *streamTrim) minID(id string) {
+	t.strategy, t.threshold = "MINID", id
+}
+
+func (t *streamTrim) args() []string {
+	if t.strategy == "" {
+		return nil
+	}
+	args := []string{t.strategy}
+	if t.approx {
+		args = append(args, "~")
+	}
+	args = append(args, t.threshold)
+	if t.limit > 0 {
+		args = append(args, "LIMIT", strconv.Itoa(t.limit))
+	}
+	return args
+}
+
+// UnmarshalRESP implements the resp.Unmarshaler interface.
+func (e *StreamEntry) UnmarshalRESP(br *bufio.Reader) error {
+	var ah resp2.ArrayHeader
+	if err := ah.UnmarshalRESP(br); err != nil {
+		return err
+	}
+	*e = StreamEntry{}
+	if ah.N < 0 {
+		// entry deleted while pending (XCLAIM)
+		return nil
+	}
+	if ah.N != 2 {
+		return errors.New("cyclone: invalid stream entry")
+	}
+
+	var bs resp2.BulkString
+	if err := bs.UnmarshalRESP(br); err != nil {
+		return err
+	}
+	e.ID = bs.S
+
+	if err := ah.UnmarshalRESP(br); err != nil {
+		return err
+	}
+	if ah.N < 0 {
+		return nil
+	}
+	e.Fields = make(map[string]string, ah.N/2)
+	for i := 0; i < ah.N/2; i++ {
+		if err := bs.UnmarshalRESP(br); err != nil {
+			return err
+		}
+		field := bs.S
+		if err := bs.UnmarshalRESP(br); err != nil {
+			return err
+		}
+		e.Fields[field] = bs.S
+	}
+	return nil
+}
+
+// streamReply is a single stream reply of XREAD and XREADGROUP.
+type streamReply struct {
+	stream  string
+	entries []StreamEntry
+}
+
+func (r *streamReply) UnmarshalRESP(br *bufio.Reader) error {
+	var ah resp2.ArrayHeader
+	if err := ah.UnmarshalRESP(br); err != nil {
+		return err
+	}
+	if ah.N != 2 {
+		return errors.New("cyclone: invalid XREAD reply")
+	}
+
+	var bs resp2.BulkString
+	if err := bs.UnmarshalRESP(br); err != nil {
+		return err
+	}
+	r.stream = bs.S
+	return resp2.Any{I: &r.entries}.UnmarshalRESP(br)
+}
+
+// autoClaimReply is a reply of XAUTOCLAIM. Deleted IDs
+// returned by redis 7 are discarded.
+type autoClaimReply struct {
+	next    string
+	entries []StreamEntry
+}
+
+func (r *autoClaimReply) UnmarshalRESP(br *bufio.Reader) error {
+	var ah resp2.ArrayHeader
+	if err := ah.UnmarshalRESP(br); err != nil {
+		return err
+	}
+	if ah.N < 2 {
+		return errors.New("cyclone: invalid XAUTOCLAIM reply")
+	}
+
+	var bs resp2.BulkString
+	if err := bs.UnmarshalRESP(br); err != nil {
+		return err
+	}
+	r.next = bs.S
+	if err := (resp2.Any{I: &r.entries}).UnmarshalRESP(br); err != nil {
+		return err
+	}
+	for i := 2; i < ah.N; i++ {
+		if err := (resp2.Any{}).UnmarshalRESP(br); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replyString converts bulk string element of []interface{} reply.
+func replyString(v interface{}) string {
+	b, _ := v.([]byte)
+	return string(b)
+}
+
+// replyInt converts integer (or numeric bulk string) element of []interface{} reply.
+func replyInt(v interface{}) int {
+	switch n := v.(type) {
+	case int64:
+		return int(n)
+	case []byte:
+		i, _ := strconv.Atoi(string(n))
+		return i
+	}
+	return 0
+}
@@ -0,0 +1,257 @@
+package cyclone
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/franela/goblin"
+)
+
+func TestStream(t *testing.T) {
+	g := goblin.Goblin(t)
+
+	ids := func(entries []StreamEntry, err error) []string {
+		ids := []string{}
+		for _, e := range entries {
+			ids = append(ids, e.ID)
+		}
+		return ids
+	}
+
+	withConn(func(c *Cyclone) {
+		g.Describe(".Add", func() {
+			g.It("Adds entries with generated IDs", func() {
+				s := c.Stream("StreamAdd")
+
+				id, err := s.Add("a", 1, "b", "x")
+				g.Assert(err).Eql(nil)
+				g.Assert(id == "").IsFalse()
+
+				s.Add(map[string]string{"c": "3"})
+
+				entries, _ := s.Range("-", "+", 0)
+				g.Assert(len(entries)).Eql(2)
+				g.Assert(entries[0]).Eql(StreamEntry{ID: id, Fields: map[string]string{"a": "1", "b": "x"}})
+				g.Assert(entries[1].Fields).Eql(map[string]string{"c": "3"})
+			})
+
+			g.It("Returns ErrWrongType for non-stream keys", func() {
+				c.Set("StreamAddSet").Add("a")
+
+				_, err := c.Stream("StreamAddSet").Add("a", 1)
+				g.Assert(err).Eql(ErrWrongType)
+			})
+		})
+
+		g.Describe(".AddWith", func() {
+			g.It("Adds entry with explicit ID", func() {
+				id, _ := c.Stream("StreamAddWithID").AddWith("a", 1).ID("5-1").Do()
+				g.Assert(id).Eql("5-1")
+
+				_, err := c.Stream("StreamAddWithID").AddWith("a", 1).ID("4-1").Do()
+				g.Assert(err == nil).IsFalse()
+			})
+
+			g.It("Does not create stream with NoMkStream", func() {
+				_, err := c.Stream("StreamAddWithNoMk").AddWith("a", 1).NoMkStream().Do()
+				g.Assert(err).Eql(ErrNil)
+			})
+
+			g.It("Trims stream with MaxLen and MinID", func() {
+				s := c.Stream("StreamAddWithTrim")
+				for i := 1; i <= 5; i++ {
+					s.AddWith("a", i).ID(strconv.Itoa(i) + "-0").MaxLen(3).Do()
+				}
+				g.Assert(ids(s.Range("-", "+", 0))).Eql([]string{"3-0", "4-0", "5-0"})
+
+				s.AddWith("a", 6).ID("6-0").MinID("5").Do()
+				g.Assert(ids(s.Range("-", "+", 0))).Eql([]string{"5-0", "6-0"})
+			})
+		})
+
+		g.Describe(".Del", func() {
+			g.It("Deletes entries", func() {
+				s := c.Stream("StreamDel")
+				id, _ := s.Add("a", 1)
+				s.Add("a", 2)
+
+				deleted, _ := s.Del(id, "0-1")
+				g.Assert(deleted).Eql(1)
+
+				length, _ := s.Len()
+				g.Assert(length).Eql(1)
+			})
+		})
+
+		g.Describe(".Range", func() {
+			s := c.Stream("StreamRange")
+
+			g.BeforeEach(func() {
+				for _, id := range []string{"1-0", "2-0", "3-0"} {
+					s.AddWith("a", 1).ID(id).Do()
+				}
+			})
+
+			g.It("Returns entries in range", func() {
+				g.Assert(ids(s.Range("2", "+", 0))).Eql([]string{"2-0", "3-0"})
+				g.Assert(ids(s.Range("-", "+", 2))).Eql([]string{"1-0", "2-0"})
+			})
+
+			g.It("Returns entries in reverse order", func() {
+				g.Assert(ids(s.RevRange("+", "-", 0))).Eql([]string{"3-0", "2-0", "1-0"})
+				g.Assert(ids(s.RevRange("2", "-", 1))).Eql([]string{"2-0"})
+			})
+		})
+
+		g.Describe(".Trim", func() {
+			g.It("Trims stream", func() {
+				s := c.Stream("StreamTrim")
+				for _, id := range []string{"1-0", "2-0", "3-0"} {
+					s.AddWith("a", 1).ID(id).Do()
+				}
+
+				trimmed, err := s.Trim().MaxLen(2).Do()
+				g.Assert(trimmed).Eql(1)
+				g.Assert(err).Eql(nil)
+
+				trimmed, _ = s.Trim().MinID("3").Do()
+				g.Assert(trimmed).Eql(1)
+
+				_, err = s.Trim().Do()
+				g.Assert(err == nil).IsFalse()
+			})
+		})
+
+		g.Describe(".Read", func() {
+			g.It("Reads entries after ID", func() {
+				s := c.Stream("StreamRead")
+				for _, id := range []string{"1-0", "2-0", "3-0"} {
+					s.AddWith("a", 1).ID(id).Do()
+				}
+
+				g.Assert(ids(s.Read("1-0").Do())).Eql([]string{"2-0", "3-0"})
+				g.Assert(ids(s.Read("0").Count(1).Do())).Eql([]string{"1-0"})
+				g.Assert(ids(s.Read("3-0").Do())).Eql([]string{})
+			})
+
+			g.It("Waits for entries to be added", func() {
+				s := c.Stream("StreamReadBlock")
+				go func() {
+					time.Sleep(50 * time.Millisecond)
+					s.AddWith("a", 1).ID("1-0").Do()
+				}()
+
+				g.Assert(ids(s.Read("$").Block(2 * time.Second).Do())).Eql([]string{"1-0"})
+			})
+
+			g.It("Returns no entries on timeout", func() {
+				entries, err := c.Stream("StreamReadTimeout").Read("$").Block(100 * time.Millisecond).Do()
+				g.Assert(len(entries)).Eql(0)
+				g.Assert(err).Eql(nil)
+			})
+
+			g.It("Does not block forever on sub-millisecond timeout", func() {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+
+				entries, err := c.WithContext(ctx).Stream("StreamReadShort").Read("$").Block(500 * time.Microsecond).Do()
+				g.Assert(len(entries)).Eql(0)
+				g.Assert(err).Eql(nil)
+			})
+
+			g.It("Is interrupted by context", func() {
+				ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+				defer cancel()
+
+				_, err := c.WithContext(ctx).Stream("StreamReadCtx").Read("$").Block(0).Do()
+				g.Assert(err).Eql(context.DeadlineExceeded)
+			})
+		})
+
+		g.Describe(".Group", func() {
+			g.It("Creates and destroys group", func() {
+				group := c.Stream("StreamGroupCreate").Group("g")
+
+				g.Assert(group.Create("$", false) == nil).IsFalse()
+				g.Assert(group.Create("$", true)).Eql(nil)
+				g.Assert(group.Create("$", true)).Eql(ErrBusyGroup)
+
+				destroyed, _ := group.Destroy()
+				g.Assert(destroyed).IsTrue()
+				destroyed, _ = group.Destroy()
+				g.Assert(destroyed).IsFalse()
+			})
+
+			g.It("Reads and acknowledges entries", func() {
+				s := c.Stream("StreamGroupRead")
+				group := s.Group("g")
+				group.Create("0", true)
+				for _, id := range []string{"1-0", "2-0"} {
+					s.AddWith("a", 1).ID(id).Do()
+				}
+
+				entries, err := group.Read("alice", ">").Count(1).Do()
+				g.Assert(err).Eql(nil)
+				g.Assert(ids(entries, nil)).Eql([]string{"1-0"})
+				g.Assert(ids(group.Read("bob", ">").Do())).Eql([]string{"2-0"})
+
+				// pending entries of the consumer
+				g.Assert(ids(group.Read("alice", "0").Do())).Eql([]string{"1-0"})
+
+				summary, _ := group.PendingSummary()
+				g.Assert(summary).Eql(StreamPendingSummary{
+					Count:     2,
+					Lowest:    "1-0",
+					Highest:   "2-0",
+					Consumers: map[string]int{"alice": 1, "bob": 1},
+				})
+
+				acked, _ := group.Ack("1-0", "2-0", "3-0")
+				g.Assert(acked).Eql(2)
+
+				summary, _ = group.PendingSummary()
+				g.Assert(summary.Count).Eql(0)
+				g.Assert(summary.Consumers).Eql(map[string]int{})
+			})
+
+			g.It("Returns ErrNoGroup for missing group", func() {
+				c.Stream("StreamGroupMissing").Add("a", 1)
+
+				_, err := c.Stream("StreamGroupMissing").Group("g").Read("alice", ">").Do()
+				g.Assert(err).Eql(ErrNoGroup)
+			})
+
+			g.It("Lists and claims pending entries", func() {
+				s := c.Stream("StreamGroupClaim")
+				group := s.Group("g")
+				group.Create("0", true)
+				for _, id := range []string{"1-0", "2-0", "3-0"} {
+					s.AddWith("a", 1).ID(id).Do()
+				}
+				group.Read("alice", ">").Do()
+
+				pending, _ := group.Pending("-", "+", 10)
+				g.Assert(len(pending)).Eql(3)
+				g.Assert(pending[0].ID).Eql("1-0")
+				g.Assert(pending[0].Consumer).Eql("alice")
+				g.Assert(pending[0].Deliveries).Eql(1)
+
+				g.Assert(ids(group.Claim("bob", 0, "1-0"))).Eql([]string{"1-0"})
+
+				next, entries, err := group.AutoClaim("carol", 0, "0-0", 2)
+				g.Assert(err).Eql(nil)
+				g.Assert(ids(entries, nil)).Eql([]string{"1-0", "2-0"})
+
+				next, entries, _ = group.AutoClaim("carol", 0, next, 2)
+				g.Assert(ids(entries, nil)).Eql([]string{"3-0"})
+				g.Assert(next).Eql("0-0")
+
+				pending, _ = group.Pending("-", "+", 1)
+				g.Assert(pending[0].Consumer).Eql("carol")
+				g.Assert(pending[0].Deliveries).Eql(3)
+			})
+		})
+	})
+}
@@ -0,0 +1,277 @@
+package cyclone
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultStreamWorkerCount is used by StreamWorker when StreamWorkerOptions.Count is not positive.
+	DefaultStreamWorkerCount = 10
+
+	// DefaultStreamWorkerBlock is used by StreamWorker when StreamWorkerOptions.Block is not positive.
+	DefaultStreamWorkerBlock = 5 * time.Second
+
+	// DefaultStreamWorkerMinIdle is used by StreamWorker when StreamWorkerOptions.MinIdle is not positive.
+	DefaultStreamWorkerMinIdle = time.Minute
+
+	// DefaultStreamWorkerClaimInterval is used by StreamWorker when
+	// StreamWorkerOptions.ClaimInterval is not positive.
+	DefaultStreamWorkerClaimInterval = 30 * time.Second
+)
+
+// StreamHandler processes a single stream entry. Entry is acknowledged
+// when nil is returned.
+type StreamHandler func(ctx context.Context, entry StreamEntry) error
+
+// StreamWorkerOptions configures StreamWorker.
+type StreamWorkerOptions struct {
+	// Consumer is the consumer name prefix, each goroutine reads
+	// as Consumer-<n>. Defaults to hostname.
+	Consumer string
+
+	// Concurrency is the number of goroutines reading the group. Defaults to 1.
+	Concurrency int
+
+	// Count is the maximum number of entries read (or claimed) at once.
+	// Defaults to DefaultStreamWorkerCount.
+	Count int
+
+	// Block is how long a single read waits for new entries.
+	// Defaults to DefaultStreamWorkerBlock.
+	Block time.Duration
+
+	// Retries is how many times a failed entry is retried right away.
+	// Entries still failing stay pending and are claimed again after MinIdle.
+	Retries int
+
+	// Backoff returns delay before given retry attempt (starting at 1).
+	// Defaults to exponential backoff from 100ms up to 10s.
+	Backoff func(attempt int) time.Duration
+
+	// MinIdle is how long an entry must stay pending before it is claimed
+	// from its (possibly dead) consumer. Defaults to DefaultStreamWorkerMinIdle.
+	MinIdle time.Duration
+
+	// ClaimInterval is how often stale pending entries are claimed.
+	// Defaults to DefaultStreamWorkerClaimInterval.
+	ClaimInterval time.Duration
+
+	// MaxDeliveries is the number of deliveries after which a claimed entry
+	// is moved to DeadLetter instead of being handled. Zero means unlimited.
+	MaxDeliveries int
+
+	// DeadLetter receives entries exceeding MaxDeliveries. They are only
+	// acknowledged (dropped) when DeadLetter is nil.
+	DeadLetter *Stream
+
+	// OnError is called with read, claim and handler errors.
+	OnError func(err error)
+}
+
+// StreamWorker processes entries of a stream consumer group.
+type StreamWorker struct {
+	group   *StreamGroup
+	handler StreamHandler
+	opts    StreamWorkerOptions
+}
+
+// NewStreamWorker returns worker running handler for entries of group.
+//
+//   w := cyclone.NewStreamWorker(c.Stream("events").Group("workers"), handle, cyclone.StreamWorkerOptions{
+//     Concurrency:   4,
+//     Retries:       3,
+//     MaxDeliveries: 10,
+//     DeadLetter:    c.Stream("events:dead"),
+//   })
+//   err := w.Run(ctx)
+func NewStreamWorker(group *StreamGroup, handler StreamHandler, opts StreamWorkerOptions) *StreamWorker {
+	if opts.Consumer == "" {
+		opts.Consumer, _ = os.Hostname()
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 1
+	}
+	if opts.Count <= 0 {
+		opts.Count = DefaultStreamWorkerCount
+	}
+	if opts.Block <= 0 {
+		opts.Block = DefaultStreamWorkerBlock
+	}
+	if opts.Backoff == nil {
+		opts.Backoff = defaultBackoff
+	}
+	if opts.MinIdle <= 0 {
+		opts.MinIdle = DefaultStreamWorkerMinIdle
+	}
+	if opts.ClaimInterval <= 0 {
+		opts.ClaimInterval = DefaultStreamWorkerClaimInterval
+	}
+	if opts.OnError == nil {
+		opts.OnError = func(error) {}
+	}
+	return &StreamWorker{group: group, handler: handler, opts: opts}
+}
+
+// Run creates the group when it does not exist (reading new entries only)
+// and processes entries until ctx is done. It waits for running handlers
+// to return before returning.
+func (w *StreamWorker) Run(ctx context.Context) error {
+	stream := w.group.stream.cyclone.WithContext(ctx).Stream(w.group.stream.key)
+	group := stream.Group(w.group.name)
+
+	if err := group.Create("$", true); err != nil && err != ErrBusyGroup {
+		return err
+	}
+
+	ticker := time.NewTicker(w.opts.ClaimInterval)
+	defer ticker.Stop()
+
+	var wg sync.WaitGroup
+	for i := 0; i < w.opts.Concurrency; i++ {
+		wg.Add(1)
+		go func(consumer string) {
+			defer wg.Done()
+			w.work(ctx, group, consumer, ticker.C)
+		}(fmt.Sprintf("%s-%d", w.opts.Consumer, i))
+	}
+	wg.Wait()
+	return nil
+}
+
+// work reads new entries as consumer, claim ticks are shared by all workers,
+// so stale entries are claimed by one of them.
+func (w *StreamWorker) work(ctx context.Context, group *StreamGroup, consumer string, claim <-chan time.Time) {
+	failures := 0
+	for ctx.Err() == nil {
+		select {
+		case <-claim:
+			w.claim(ctx, group, consumer)
+		default:
+		}
+
+		entries, err := group.Read(consumer, ">").Count(w.opts.Count).Block(w.opts.Block).Do()
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			failures++
+			w.opts.OnError(err)
+			sleep(ctx, w.opts.Backoff(failures))
+			continue
+		}
+		failures = 0
+
+		for _, entry := range entries {
+			w.handle(ctx, entry)
+		}
+	}
+}
+
+// claim takes over entries idle for at least MinIdle.
+func (w *StreamWorker) claim(ctx context.Context, group *StreamGroup, consumer string) {
+	start := "0-0"
+	for ctx.Err() == nil {
+		next, entries, err := group.AutoClaim(consumer, w.opts.MinIdle, start, w.opts.Count)
+		if err != nil {
+			w.opts.OnError(err)
+			return
+		}
+
+		for _, entry := range entries {
+			if w.exceeded(group, entry) {
+				w.deadLetter(entry)
+				continue
+			}
+			w.handle(ctx, entry)
+		}
+
+		if next == "0-0" || next == "" {
+			return
+		}
+		start = next
+	}
+}
+
+// exceeded checks if entry was delivered more than MaxDeliveries times.
+func (w *StreamWorker) exceeded(group *StreamGroup, entry StreamEntry) bool {
+	if w.opts.MaxDeliveries <= 0 || entry.Fields == nil {
+		return false
+	}
+	pending, err := group.Pending(entry.ID, entry.ID, 1)
+	if err != nil {
+		w.opts.OnError(err)
+		return false
+	}
+	return len(pending) == 1 && pending[0].Deliveries > w.opts.MaxDeliveries
+}
+
+// deadLetter moves entry to DeadLetter stream.
+func (w *StreamWorker) deadLetter(entry StreamEntry) {
+	if w.opts.DeadLetter != nil {
+		fields := make([]interface{}, 0, 2*len(entry.Fields))
+		for k, v := range entry.Fields {
+			fields = append(fields, k, v)
+		}
+		if _, err := w.opts.DeadLetter.Add(fields...); err != nil {
+			w.opts.OnError(err)
+			return
+		}
+	}
+	w.ack(entry)
+}
+
+// handle runs handler with retries, entry is acknowledged on success.
+func (w *StreamWorker) handle(ctx context.Context, entry StreamEntry) {
+	if entry.Fields == nil {
+		// deleted while pending, nothing to handle
+		w.ack(entry)
+		return
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := w.handler(ctx, entry)
+		if err == nil {
+			w.ack(entry)
+			return
+		}
+		if attempt >= w.opts.Retries || ctx.Err() != nil {
+			w.opts.OnError(fmt.Errorf("cyclone: handling stream entry %s: %w", entry.ID, err))
+			return
+		}
+		sleep(ctx, w.opts.Backoff(attempt+1))
+	}
+}
+
+func (w *StreamWorker) ack(entry StreamEntry) {
+	// group passed to the worker is not bound to Run context,
+	// so entry handled during shutdown is still acknowledged
+	if _, err := w.group.Ack(entry.ID); err != nil {
+		w.opts.OnError(err)
+	}
+}
+
+// defaultBackoff doubles delay from 100ms up to 10s.
+func defaultBackoff(attempt int) time.Duration {
+	d := 100 * time.Millisecond
+	for i := 1; i < attempt && d < 10*time.Second; i++ {
+		d *= 2
+	}
+	if d > 10*time.Second {
+		d = 10 * time.Second
+	}
+	return d
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,186 @@
+package cyclone
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/franela/goblin"
+)
+
+func TestStreamWorker(t *testing.T) {
+	g := goblin.Goblin(t)
+
+	// run starts worker in background and returns function stopping it.
+	run := func(w *StreamWorker) func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			w.Run(ctx)
+			close(done)
+		}()
+		return func() {
+			cancel()
+			<-done
+		}
+	}
+
+	// eventually polls cond until it is true or a second passes.
+	eventually := func(cond func() bool) bool {
+		for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); {
+			if cond() {
+				return true
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		return cond()
+	}
+
+	pendingCount := func(group *StreamGroup) int {
+		summary, _ := group.PendingSummary()
+		return summary.Count
+	}
+
+	withConn(func(c *Cyclone) {
+		g.Describe(".Run", func() {
+			g.It("Handles and acknowledges new entries", func() {
+				s := c.Stream("StreamWorkerRun")
+				group := s.Group("g")
+
+				var mu sync.Mutex
+				handled := map[string]string{}
+				w := NewStreamWorker(group, func(ctx context.Context, e StreamEntry) error {
+					mu.Lock()
+					defer mu.Unlock()
+					handled[e.ID] = e.Fields["n"]
+					return nil
+				}, StreamWorkerOptions{Concurrency: 2, Block: 50 * time.Millisecond})
+				stop := run(w)
+				defer stop()
+
+				g.Assert(eventually(func() bool {
+					_, err := group.PendingSummary()
+					return err == nil
+				})).IsTrue()
+				for i := 0; i < 5; i++ {
+					s.Add("n", i)
+				}
+
+				g.Assert(eventually(func() bool {
+					mu.Lock()
+					defer mu.Unlock()
+					return len(handled) == 5
+				})).IsTrue()
+				g.Assert(eventually(func() bool { return pendingCount(group) == 0 })).IsTrue()
+			})
+
+			g.It("Retries failed entries with backoff", func() {
+				s := c.Stream("StreamWorkerRetry")
+				group := s.Group("g")
+				group.Create("0", true)
+				s.Add("a", 1)
+
+				var mu sync.Mutex
+				attempts, backoffs := 0, []int{}
+				w := NewStreamWorker(group, func(ctx context.Context, e StreamEntry) error {
+					mu.Lock()
+					defer mu.Unlock()
+					attempts++
+					if attempts < 3 {
+						return errors.New("failed")
+					}
+					return nil
+				}, StreamWorkerOptions{
+					Block:   50 * time.Millisecond,
+					Retries: 2,
+					Backoff: func(attempt int) time.Duration {
+						mu.Lock()
+						defer mu.Unlock()
+						backoffs = append(backoffs, attempt)
+						return time.Millisecond
+					},
+				})
+				stop := run(w)
+				defer stop()
+
+				g.Assert(eventually(func() bool {
+					mu.Lock()
+					defer mu.Unlock()
+					return attempts == 3
+				})).IsTrue()
+				g.Assert(eventually(func() bool { return pendingCount(group) == 0 })).IsTrue()
+
+				mu.Lock()
+				defer mu.Unlock()
+				g.Assert(backoffs).Eql([]int{1, 2})
+			})
+
+			g.It("Claims stale entries of dead consumers", func() {
+				s := c.Stream("StreamWorkerClaim")
+				group := s.Group("g")
+				group.Create("0", true)
+				s.AddWith("a", 1).ID("1-0").Do()
+				group.Read("dead", ">").Do()
+
+				handled := make(chan string, 1)
+				w := NewStreamWorker(group, func(ctx context.Context, e StreamEntry) error {
+					handled <- e.ID
+					return nil
+				}, StreamWorkerOptions{
+					Block:         20 * time.Millisecond,
+					MinIdle:       10 * time.Millisecond,
+					ClaimInterval: 20 * time.Millisecond,
+				})
+				stop := run(w)
+				defer stop()
+
+				select {
+				case id := <-handled:
+					g.Assert(id).Eql("1-0")
+				case <-time.After(time.Second):
+					g.Fail("entry was not claimed")
+				}
+				g.Assert(eventually(func() bool { return pendingCount(group) == 0 })).IsTrue()
+			})
+
+			g.It("Moves entries exceeding deliveries to dead letter stream", func() {
+				s := c.Stream("StreamWorkerDead")
+				dead := c.Stream("StreamWorkerDeadLetter")
+				group := s.Group("g")
+				group.Create("0", true)
+				s.Add("a", 1)
+
+				errs := make(chan error, 100)
+				w := NewStreamWorker(group, func(ctx context.Context, e StreamEntry) error {
+					return errors.New("failed")
+				}, StreamWorkerOptions{
+					Block:         20 * time.Millisecond,
+					MinIdle:       10 * time.Millisecond,
+					ClaimInterval: 20 * time.Millisecond,
+					MaxDeliveries: 2,
+					DeadLetter:    dead,
+					OnError: func(err error) {
+						select {
+						case errs <- err:
+						default:
+						}
+					},
+				})
+				stop := run(w)
+				defer stop()
+
+				g.Assert(eventually(func() bool {
+					length, _ := dead.Len()
+					return length == 1
+				})).IsTrue()
+				g.Assert(eventually(func() bool { return pendingCount(group) == 0 })).IsTrue()
+
+				entries, _ := dead.Range("-", "+", 0)
+				g.Assert(entries[0].Fields).Eql(map[string]string{"a": "1"})
+				g.Assert(len(errs) >= 2).IsTrue()
+			})
+		})
+	})
+}
@@ -0,0 +1,308 @@
+package cyclone
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/mediocregopher/radix/v3"
+)
+
+// String wraps redis string operations.
+type String struct {
+	Key
+}
+
+// StringSet is a SET command builder.
+type StringSet struct {
+	str   *String
+	value interface{}
+	args  []interface{}
+}
+
+// Append appends the value at the end of the string. If key does not exist
+// it is created and set as an empty string, so Append will be similar to Set
+// in this special case.
+// https://redis.io/commands/append
+//
+// Time complexity: O(1)
+func (s *String) Append(value string) (lenAfterAppend int, err error) {
+	err = s.cyclone.do(radix.Cmd(&lenAfterAppend, "APPEND", s.key, value))
+	return
+}
+
+// Get returns the value of key. ErrNil is returned when key does not exist.
+// https://redis.io/commands/get
+//
+// Time complexity: O(1)
+func (s *String) Get() (value string, err error) {
+	mn := radix.MaybeNil{Rcv: &value}
+	err = s.cyclone.do(radix.Cmd(&mn, "GET", s.key))
+	err = nilErr(&mn, err)
+	return
+}
+
+// GetDel gets the value of key and deletes the key.
+// ErrNil is returned when key does not exist.
+// https://redis.io/commands/getdel
+//
+// Time complexity: O(1)
+func (s *String) GetDel() (value string, err error) {
+	mn := radix.MaybeNil{Rcv: &value}
+	err = s.cyclone.do(radix.Cmd(&mn, "GETDEL", s.key))
+	err = nilErr(&mn, err)
+	return
+}
+
+// GetEx gets the value of key and sets its expiration (GETEX with PX option).
+// ErrNil is returned when key does not exist.
+// https://redis.io/commands/getex
+//
+// Time complexity: O(1)
+func (s *String) GetEx(ttl time.Duration) (value string, err error) {
+	mn := radix.MaybeNil{Rcv: &value}
+	err = s.cyclone.do(radix.Cmd(&mn, "GETEX", s.key, "PX", formatMs(ttl)))
+	err = nilErr(&mn, err)
+	return
+}
+
+// GetPersist gets the value of key and removes its expiration (GETEX with PERSIST option).
+// ErrNil is returned when key does not exist.
+// https://redis.io/commands/getex
+//
+// Time complexity: O(1)
+func (s *String) GetPersist() (value string, err error) {
+	mn := radix.MaybeNil{Rcv: &value}
+	err = s.cyclone.do(radix.Cmd(&mn, "GETEX", s.key, "PERSIST"))
+	err = nilErr(&mn, err)
+	return
+}
+
+// GetRange returns the substring of the string value stored at key, determined
+// by the offsets start and end (both are inclusive). Negative offsets can be used
+// in order to provide an offset starting from the end of the string.
+// https://redis.io/commands/getrange
+//
+// Time complexity: O(N) where N is the length of the returned string.
+func (s *String) GetRange(start, end int) (value string, err error) {
+	err = s.cyclone.do(radix.Cmd(
+		&value,
+		"GETRANGE",
+		s.key,
+		strconv.Itoa(start),
+		strconv.Itoa(end),
+	))
+	return
+}
+
+// Incr increments the number stored at key by increment. If the key does not exist,
+// it is set to 0 before performing the operation.
+// https://redis.io/commands/incrby
+//
+// Time complexity: O(1)
+func (s *String) Incr(by int) (valAfterIncr int, err error) {
+	err = s.cyclone.do(radix.Cmd(
+		&valAfterIncr,
+		"INCRBY",
+		s.key,
+		strconv.FormatInt(int64(by), 10),
+	))
+	return
+}
+
+// IncrFloat increments the string representing a floating point number stored
+// at key by the specified increment. If the key does not exist, it is set to 0
+// before performing the operation.
+// https://redis.io/commands/incrbyfloat
+//
+// Time complexity: O(1)
+func (s *String) IncrFloat(by float64) (valAfterIncr float64, err error) {
+	err = s.cyclone.do(radix.Cmd(
+		&valAfterIncr,
+		"INCRBYFLOAT",
+		s.key,
+		strconv.FormatFloat(by, 'E', -1, 64),
+	))
+	return
+}
+
+// Set sets key to hold the string value. If key already holds a value,
+// it is overwritten, regardless of its type. Any previous time to live
+// associated with the key is discarded. Use SetWith for options.
+// https://redis.io/commands/set
+//
+// Time complexity: O(1)
+func (s *String) Set(value interface{}) error {
+	return s.cyclone.do(radix.FlatCmd(nil, "SET", s.key, value))
+}
+
+// SetRange overwrites part of the string stored at key, starting at the specified
+// offset, for the entire length of value. Non-existing keys are considered
+// as empty strings.
+// https://redis.io/commands/setrange
+//
+// Time complexity: O(1), not counting the time taken to copy the new string in place.
+func (s *String) SetRange(offset int, value string) (lenAfterSet int, err error) {
+	err = s.cyclone.do(radix.Cmd(
+		&lenAfterSet,
+		"SETRANGE",
+		s.key,
+		strconv.Itoa(offset),
+		value,
+	))
+	return
+}
+
+// SetWith returns SET command builder with EX/PX/NX/XX/KEEPTTL/GET options.
+// https://redis.io/commands/set
+//
+//   ok, err := c.String("lock").SetWith("owner").PX(time.Second).NX().Do()
+func (s *String) SetWith(value interface{}) *StringSet {
+	return &StringSet{str: s, value: value}
+}
+
+// StrLen returns the length of the string value stored at key.
+// https://redis.io/commands/strlen
+//
+// Time complexity: O(1)
+func (s *String) StrLen() (length int, err error) {
+	err = s.cyclone.do(radix.Cmd(&length, "STRLEN", s.key))
+	return
+}
+
+// EX sets the specified expire time, in seconds. Positive ttl shorter than a second
+// is rounded up to a second.
+func (b *StringSet) EX(ttl time.Duration) *StringSet {
+	b.args = append(b.args, "EX", formatSec(ttl))
+	return b
+}
+
+// PX sets the specified expire time, in milliseconds.
+func (b *StringSet) PX(ttl time.Duration) *StringSet {
+	b.args = append(b.args, "PX", formatMs(ttl))
+	return b
+}
+
+// NX only sets the key if it does not already exist.
+func (b *StringSet) NX() *StringSet {
+	b.args = append(b.args, "NX")
+	return b
+}
+
+// XX only sets the key if it already exists.
+func (b *StringSet) XX() *StringSet {
+	b.args = append(b.args, "XX")
+	return b
+}
+
+// KeepTTL retains the time to live associated with the key.
+func (b *StringSet) KeepTTL() *StringSet {
+	b.args = append(b.args, "KEEPTTL")
+	return b
+}
+
+// Do executes SET. It returns false when the value was not set
+// because of NX or XX condition.
+func (b *StringSet) Do() (wasSet bool, err error) {
+	mn := radix.MaybeNil{}
+	err = b.str.cyclone.do(radix.FlatCmd(&mn, "SET", b.str.key, b.flatArgs()...))
+	return err == nil && !mn.Nil, err
+}
+
+// Get executes SET with GET option and returns the old string stored at key.
+// ErrNil is returned when key did not exist.
+func (b *StringSet) Get() (old string, err error) {
+	mn := radix.MaybeNil{Rcv: &old}
+	args := append(b.flatArgs(), "GET")
+	err = b.str.cyclone.do(radix.FlatCmd(&mn, "SET", b.str.key, args...))
+	err = nilErr(&mn, err)
+	return
+}
+
+func (b *StringSet) flatArgs() []interface{} {
+	args := make([]interface{}, 0, len(b.args)+2)
+	args = append(args, b.value)
+	return append(args, b.args...)
+}
+
+// MGet returns the values of all specified keys. For every key that does not hold
+// a string value or does not exist, empty string is returned.
+// https://redis.io/commands/mget
+//
+// Time complexity: O(N) where N is the number of keys to retrieve.
+func (c *Cyclone) MGet(keys ...string) (values []string, err error) {
+	err = c.do(radix.Cmd(&values, "MGET", keys...))
+	return
+}
+
+// MSet sets the given keys to their respective values.
+// MSet replaces existing values with new values, just as regular Set.
+// https://redis.io/commands/mset
+//
+// Time complexity: O(N) where N is the number of keys to set.
+func (c *Cyclone) MSet(kvpairs ...interface{}) error {
+	cmd, err := msetCmd(nil, "MSET", kvpairs)
+	if err != nil {
+		return err
+	}
+	return c.do(cmd)
+}
+
+// MSetNX sets the given keys to their respective values. MSetNX will not
+// perform any operation at all even if just a single key already exists.
+// https://redis.io/commands/msetnx
+//
+// Time complexity: O(N) where N is the number of keys to set.
+func (c *Cyclone) MSetNX(kvpairs ...interface{}) (bool, error) {
+	var wasSet int
+	cmd, err := msetCmd(&wasSet, "MSETNX", kvpairs)
+	if err != nil {
+		return false, err
+	}
+	err = c.do(cmd)
+	return wasSet == 1, err
+}
+
+// msetCmd returns MSET or MSETNX of kvpairs flattened the same way as by radix.FlatCmd.
+// Its Keys returns every key, so radix.Cluster routes (or rejects) it correctly.
+func msetCmd(rcv interface{}, cmd string, kvpairs []interface{}) (radix.CmdAction, error) {
+	kvpairs = flattenArgs(kvpairs)
+	if len(kvpairs) == 0 {
+		return nil, fmt.Errorf("cyclone: %s requires key/value pairs", cmd)
+	}
+	keys := make([]string, 0, (len(kvpairs)+1)/2)
+	for i := 0; i < len(kvpairs); i += 2 {
+		keys = append(keys, fmt.Sprint(kvpairs[i]))
+	}
+	return keysCmd{CmdAction: radix.FlatCmd(rcv, cmd, keys[0], kvpairs[1:]...), keys: keys}, nil
+}
+
+// keysCmd overrides keys of a command which has more than one key.
+type keysCmd struct {
+	radix.CmdAction
+	keys []string
+}
+
+// Keys implements radix.Action.
+func (k keysCmd) Keys() []string {
+	return k.keys
+}
+
+// formatSec formats duration in whole seconds. Positive durations shorter than
+// a second are rounded up, otherwise expire time 0 would delete the key at once.
+func formatSec(d time.Duration) string {
+	if d > 0 && d < time.Second {
+		d = time.Second
+	}
+	return strconv.FormatInt(int64(d/time.Second), 10)
+}
+
+// formatMs formats duration in whole milliseconds. Positive durations shorter
+// than a millisecond are rounded up, as 0 deletes the key (PEXPIRE), never
+// expires it (RESTORE) or blocks forever (BLOCK).
+func formatMs(d time.Duration) string {
+	if d > 0 && d < time.Millisecond {
+		d = time.Millisecond
+	}
+	return strconv.FormatInt(int64(d/time.Millisecond), 10)
+}
@@ -0,0 +1,216 @@
+package cyclone
+
+import (
+	"testing"
+	"time"
+
+	"github.com/franela/goblin"
+	"github.com/mediocregopher/radix/v3"
+)
+
+func TestString(t *testing.T) {
+	g := goblin.Goblin(t)
+
+	withConn(func(c *Cyclone) {
+		g.Describe(".Append", func() {
+			g.It("Appends value and returns length", func() {
+				str := c.String("StringAppend")
+
+				length, _ := str.Append("ab")
+				g.Assert(length).Eql(2)
+				length, _ = str.Append("cd")
+				g.Assert(length).Eql(4)
+
+				val, _ := str.Get()
+				g.Assert(val).Eql("abcd")
+			})
+		})
+
+		g.Describe(".Get", func() {
+			g.It("Returns value or ErrNil", func() {
+				c.Raw.Do(radix.Cmd(nil, "SET", "StringGet", "a"))
+
+				val, err := c.String("StringGet").Get()
+				g.Assert(val).Eql("a")
+				g.Assert(err).Eql(nil)
+
+				_, err = c.String("StringGetMissing").Get()
+				g.Assert(err).Eql(ErrNil)
+			})
+
+			g.It("Returns ErrWrongType for non-string keys", func() {
+				c.Raw.Do(radix.Cmd(nil, "RPUSH", "StringGetList", "a"))
+
+				_, err := c.String("StringGetList").Get()
+				g.Assert(err).Eql(ErrWrongType)
+			})
+		})
+
+		g.Describe(".GetDel", func() {
+			g.It("Returns value and deletes key", func() {
+				c.String("StringGetDel").Set("a")
+
+				val, _ := c.String("StringGetDel").GetDel()
+				g.Assert(val).Eql("a")
+
+				_, err := c.String("StringGetDel").Get()
+				g.Assert(err).Eql(ErrNil)
+			})
+		})
+
+		g.Describe(".GetEx", func() {
+			g.It("Returns value and sets expiration", func() {
+				c.String("StringGetEx").Set("a")
+
+				val, _ := c.String("StringGetEx").GetEx(time.Minute)
+				g.Assert(val).Eql("a")
+
+				var ttl int
+				c.Raw.Do(radix.Cmd(&ttl, "TTL", "StringGetEx"))
+				g.Assert(ttl > 0).IsTrue()
+
+				val, _ = c.String("StringGetEx").GetPersist()
+				g.Assert(val).Eql("a")
+				c.Raw.Do(radix.Cmd(&ttl, "TTL", "StringGetEx"))
+				g.Assert(ttl).Eql(-1)
+			})
+		})
+
+		g.Describe(".GetRange", func() {
+			g.It("Returns substring", func() {
+				c.String("StringGetRange").Set("This is a string")
+
+				val, _ := c.String("StringGetRange").GetRange(0, 3)
+				g.Assert(val).Eql("This")
+				val, _ = c.String("StringGetRange").GetRange(-3, -1)
+				g.Assert(val).Eql("ing")
+			})
+		})
+
+		g.Describe(".Incr", func() {
+			g.It("Increments value", func() {
+				val, _ := c.String("StringIncr").Incr(1)
+				g.Assert(val).Eql(1)
+				val, _ = c.String("StringIncr").Incr(5)
+				g.Assert(val).Eql(6)
+			})
+		})
+
+		g.Describe(".IncrFloat", func() {
+			g.It("Increments float value", func() {
+				c.String("StringIncrFloat").Set("3.14")
+
+				val, _ := c.String("StringIncrFloat").IncrFloat(-0.43)
+				g.Assert(val).Eql(2.71)
+			})
+		})
+
+		g.Describe(".Set", func() {
+			g.It("Sets value", func() {
+				g.Assert(c.String("StringSet").Set(12)).Eql(nil)
+
+				val, _ := c.String("StringSet").Get()
+				g.Assert(val).Eql("12")
+			})
+		})
+
+		g.Describe(".SetRange", func() {
+			g.It("Overwrites part of the string", func() {
+				c.String("StringSetRange").Set("Hello World")
+
+				length, _ := c.String("StringSetRange").SetRange(6, "Redis")
+				g.Assert(length).Eql(11)
+
+				val, _ := c.String("StringSetRange").Get()
+				g.Assert(val).Eql("Hello Redis")
+			})
+		})
+
+		g.Describe(".SetWith", func() {
+			g.It("Sets value only if not exists with expiration", func() {
+				str := c.String("StringSetWithNX")
+
+				ok, err := str.SetWith("a").PX(time.Minute).NX().Do()
+				g.Assert(ok).IsTrue()
+				g.Assert(err).Eql(nil)
+
+				ok, _ = str.SetWith("b").NX().Do()
+				g.Assert(ok).IsFalse()
+
+				val, _ := str.Get()
+				g.Assert(val).Eql("a")
+
+				var ttl int
+				c.Raw.Do(radix.Cmd(&ttl, "TTL", "StringSetWithNX"))
+				g.Assert(ttl > 0).IsTrue()
+			})
+
+			g.It("Sets value only if exists keeping TTL", func() {
+				str := c.String("StringSetWithXX")
+
+				ok, _ := str.SetWith("a").XX().Do()
+				g.Assert(ok).IsFalse()
+
+				str.SetWith("a").EX(time.Minute).Do()
+				ok, _ = str.SetWith("b").XX().KeepTTL().Do()
+				g.Assert(ok).IsTrue()
+
+				var ttl int
+				c.Raw.Do(radix.Cmd(&ttl, "TTL", "StringSetWithXX"))
+				g.Assert(ttl > 0).IsTrue()
+			})
+
+			g.It("Returns old value with GET", func() {
+				str := c.String("StringSetWithGet")
+
+				_, err := str.SetWith("a").Get()
+				g.Assert(err).Eql(ErrNil)
+
+				old, err := str.SetWith("b").Get()
+				g.Assert(old).Eql("a")
+				g.Assert(err).Eql(nil)
+			})
+		})
+
+		g.Describe(".StrLen", func() {
+			g.It("Returns length of the value", func() {
+				c.String("StringStrLen").Set("ᴓ")
+
+				length, _ := c.String("StringStrLen").StrLen()
+				g.Assert(length).Eql(3)
+			})
+		})
+
+		g.Describe(".MSet", func() {
+			g.It("Sets and gets multiple keys", func() {
+				err := c.MSet("StringMSetA", "1", "StringMSetB", 2)
+				g.Assert(err).Eql(nil)
+
+				values, _ := c.MGet("StringMSetA", "StringMSetB", "StringMSetC")
+				g.Assert(values).Eql([]string{"1", "2", ""})
+
+				ok, _ := c.MSetNX("StringMSetA", "3", "StringMSetC", "3")
+				g.Assert(ok).IsFalse()
+
+				ok, _ = c.MSetNX("StringMSetC", "3", "StringMSetD", "4")
+				g.Assert(ok).IsTrue()
+			})
+
+			g.It("Flattens maps and routes by every key", func() {
+				err := c.MSet(map[string]string{"StringMSetMapA": "1", "StringMSetMapB": "2"})
+				g.Assert(err).Eql(nil)
+
+				values, _ := c.MGet("StringMSetMapA", "StringMSetMapB")
+				g.Assert(values).Eql([]string{"1", "2"})
+
+				ok, _ := c.MSetNX(map[string]int{"StringMSetMapC": 3})
+				g.Assert(ok).IsTrue()
+
+				cmd, _ := msetCmd(nil, "MSET", []interface{}{"a", 1, []string{"b", "2"}})
+				g.Assert(cmd.Keys()).Eql([]string{"a", "b"})
+
+				g.Assert(c.MSet() == nil).IsFalse()
+			})
+		})
+	})
+}
@@ -0,0 +1,267 @@
+package cyclone
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/mediocregopher/radix/v3"
+	"github.com/mediocregopher/radix/v3/resp"
+	"github.com/mediocregopher/radix/v3/resp/resp2"
+)
+
+// ErrTxConflict is returned by Tx when watched keys kept being modified
+// and the transaction could not be committed within TxMaxAttempts.
+var ErrTxConflict = errors.New("cyclone: transaction conflict, watched keys were modified")
+
+// TxMaxAttempts is the number of times Tx runs a transaction before giving up
+// with ErrTxConflict.
+var TxMaxAttempts = 10
+
+// Tx is an optimistic transaction created by Cyclone.Tx.
+//
+// Wrappers returned by Tx (Hash, List, ...) read through the watched connection,
+// so they can be used to read data the transaction depends on. Writes must be
+// queued in Pipe, only then they are executed atomically within MULTI/EXEC.
+// Writes issued by the wrappers are executed right away and are not part
+// of the transaction.
+type Tx struct {
+	cyclone *Cyclone
+	pipe    *Pipe
+}
+
+// txAction sends queued commands wrapped in MULTI/EXEC.
+type txAction []*pipeCmd
+
+var (
+	multiCmd = resp2.Any{I: []string{"MULTI"}}
+	execCmd  = resp2.Any{I: []string{"EXEC"}}
+)
+
+// Tx WATCHes keys and runs fn. Commands queued in tx.Pipe() are then executed
+// atomically in MULTI/EXEC. When any of the watched keys is modified before EXEC,
+// the transaction is discarded and fn is run again, up to TxMaxAttempts times
+// after which ErrTxConflict is returned.
+// https://redis.io/topics/transactions
+//
+// Returning an error from fn aborts the transaction and the error is returned as is.
+// Otherwise returned error is either a connection error or the first error of queued
+// commands, replied by redis or returned when a read value cannot be restored.
+//
+//   err := c.Tx([]string{"account"}, func(tx *cyclone.Tx) error {
+//     balance, err := tx.Hash("account").Get("balance")
+//     ...
+//     tx.Pipe().Hash("account").Set("balance", newBalance)
+//     return nil
+//   })
+func (c *Cyclone) Tx(keys []string, fn func(tx *Tx) error) error {
+	var key string
+	if len(keys) > 0 {
+		key = keys[0]
+	}
+
+	for attempt := 0; attempt < TxMaxAttempts; attempt++ {
+		err := c.do(radix.WithConn(key, func(conn radix.Conn) error {
+			return c.runTx(conn, keys, fn)
+		}))
+		if err != ErrTxConflict {
+			return err
+		}
+	}
+	return ErrTxConflict
+}
+
+// Pipe returns pipe in which writes of the transaction are queued.
+func (tx *Tx) Pipe() *Pipe {
+	return tx.pipe
+}
+
+// Key returns generic key wrapper reading through the watched connection.
+func (tx *Tx) Key(key string) *Key {
+	return tx.cyclone.Key(key)
+}
+
+// Keyf returns generic key wrapper reading through the watched connection.
+// Key is built from fmt.Sprintf(format, any...).
+func (tx *Tx) Keyf(format string, any ...interface{}) *Key {
+	return tx.cyclone.Keyf(format, any...)
+}
+
+// List returns list wrapper reading through the watched connection.
+func (tx *Tx) List(key string) *List {
+	return tx.cyclone.List(key)
+}
+
+// Listf returns list wrapper reading through the watched connection.
+// Key is built from fmt.Sprintf(format, any...).
+func (tx *Tx) Listf(format string, any ...interface{}) *List {
+	return tx.cyclone.Listf(format, any...)
+}
+
+// Hash returns Hash wrapper reading through the watched connection.
+func (tx *Tx) Hash(key string) *Hash {
+	return tx.cyclone.Hash(key)
+}
+
+// Hashf returns Hash wrapper reading through the watched connection.
+// Key is built from fmt.Sprintf(format, any...).
+func (tx *Tx) Hashf(format string, any ...interface{}) *Hash {
+	return tx.cyclone.Hashf(format, any...)
+}
+
+// Set returns Set wrapper reading through the watched connection.
+func (tx *Tx) Set(key string) *Set {
+	return tx.cyclone.Set(key)
+}
+
+// Setf returns Set wrapper reading through the watched connection.
+// Key is built from fmt.Sprintf(format, any...).
+func (tx *Tx) Setf(format string, any ...interface{}) *Set {
+	return tx.cyclone.Setf(format, any...)
+}
+
+// Stream returns Stream wrapper reading through the watched connection.
+func (tx *Tx) Stream(key string) *Stream {
+	return tx.cyclone.Stream(key)
+}
+
+// Streamf returns Stream wrapper reading through the watched connection.
+// Key is built from fmt.Sprintf(format, any...).
+func (tx *Tx) Streamf(format string, any ...interface{}) *Stream {
+	return tx.cyclone.Streamf(format, any...)
+}
+
+// String returns String wrapper reading through the watched connection.
+func (tx *Tx) String(key string) *String {
+	return tx.cyclone.String(key)
+}
+
+// Stringf returns String wrapper reading through the watched connection.
+// Key is built from fmt.Sprintf(format, any...).
+func (tx *Tx) Stringf(format string, any ...interface{}) *String {
+	return tx.cyclone.Stringf(format, any...)
+}
+
+// ZSet returns ZSet wrapper reading through the watched connection.
+func (tx *Tx) ZSet(key string) *ZSet {
+	return tx.cyclone.ZSet(key)
+}
+
+// ZSetf returns ZSet wrapper reading through the watched connection.
+// Key is built from fmt.Sprintf(format, any...).
+func (tx *Tx) ZSetf(format string, any ...interface{}) *ZSet {
+	return tx.cyclone.ZSetf(format, any...)
+}
+
+// MGet returns the values of all specified keys read through the watched
+// connection, see Cyclone.MGet.
+func (tx *Tx) MGet(keys ...string) ([]string, error) {
+	return tx.cyclone.MGet(keys...)
+}
+
+func (c *Cyclone) runTx(conn radix.Conn, keys []string, fn func(tx *Tx) error) error {
+	if len(keys) > 0 {
+		if err := wrapErr(conn.Do(radix.Cmd(nil, "WATCH", keys...))); err != nil {
+			return err
+		}
+	}
+
+	tx := &Tx{cyclone: &Cyclone{Raw: borrowedConn{conn}, ctx: c.ctx, codec: c.codec, transformer: c.transformer}}
+	tx.pipe = &Pipe{cyclone: tx.cyclone}
+	err := fn(tx)
+	if err == nil {
+		err = tx.pipe.encodeErr
+	}
+	if err != nil {
+		conn.Do(radix.Cmd(nil, "UNWATCH"))
+		return err
+	}
+	if len(tx.pipe.cmds) == 0 {
+		return wrapErr(conn.Do(radix.Cmd(nil, "UNWATCH")))
+	}
+
+	if err := conn.Do(txAction(tx.pipe.cmds)); err != nil {
+		tx.pipe.fail(err)
+		return err
+	}
+	return tx.pipe.err()
+}
+
+// Keys implements radix.Action.
+func (t txAction) Keys() []string {
+	return pipeAction(t).Keys()
+}
+
+// MarshalRESP implements resp.Marshaler.
+func (t txAction) MarshalRESP(w io.Writer) error {
+	if err := multiCmd.MarshalRESP(w); err != nil {
+		return err
+	}
+	if err := pipeAction(t).MarshalRESP(w); err != nil {
+		return err
+	}
+	return execCmd.MarshalRESP(w)
+}
+
+// Run implements radix.Action.
+func (t txAction) Run(conn radix.Conn) error {
+	if err := conn.Encode(t); err != nil {
+		return err
+	}
+
+	// MULTI and every queued command reply with a status,
+	// an error means command was rejected and EXEC will abort.
+	var queueErr error
+	for i := 0; i <= len(t); i++ {
+		err := conn.Decode(resp2.Any{})
+		if err != nil && !errors.As(err, new(resp.ErrDiscarded)) {
+			return err
+		}
+		if err != nil && queueErr == nil {
+			queueErr = wrapErr(err)
+		}
+	}
+
+	var reply resp2.RawMessage
+	if err := conn.Decode(&reply); err != nil {
+		return err
+	}
+
+	switch {
+	case reply.IsNil():
+		return ErrTxConflict
+	case len(reply) > 0 && reply[0] == resp2.ErrorPrefix[0]:
+		if queueErr == nil {
+			var respErr resp2.Error
+			reply.UnmarshalInto(&respErr)
+			queueErr = wrapErr(respErr)
+		}
+		return queueErr
+	}
+
+	br := bufio.NewReader(bytes.NewReader(reply))
+	var header resp2.ArrayHeader
+	if err := header.UnmarshalRESP(br); err != nil {
+		return err
+	}
+	for _, cmd := range t {
+		err := cmd.action.UnmarshalRESP(br)
+		if err != nil && !errors.As(err, new(resp.ErrDiscarded)) {
+			return err
+		}
+		cmd.complete(wrapErr(err))
+	}
+	return nil
+}
+
+// borrowedConn is a connection used by a transaction, it must not be closed
+// by the transaction itself.
+type borrowedConn struct {
+	radix.Conn
+}
+
+// Close is a no-op, borrowed connection is closed by its owner.
+func (borrowedConn) Close() error {
+	return nil
+}
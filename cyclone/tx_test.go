@@ -0,0 +1,156 @@
+package cyclone
+
+import (
+	"crypto/cipher"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/franela/goblin"
+	"github.com/mediocregopher/radix/v3"
+)
+
+func TestTx(t *testing.T) {
+	g := goblin.Goblin(t)
+
+	withConn(func(c *Cyclone) {
+		g.Describe(".Tx", func() {
+			g.It("Reads and commits queued writes", func() {
+				c.Raw.Do(radix.Cmd(nil, "HSET", "TxCommit", "balance", "10"))
+
+				var length *IntResult
+				err := c.Tx([]string{"TxCommit"}, func(tx *Tx) error {
+					balance, err := tx.Hash("TxCommit").Get("balance")
+					if err != nil {
+						return err
+					}
+					n, _ := strconv.Atoi(balance)
+
+					tx.Pipe().Hash("TxCommit").Set("balance", n-3)
+					length = tx.Pipe().List("TxCommitLog").RPush("-3")
+					return nil
+				})
+				g.Assert(err).Eql(nil)
+
+				val, _ := length.Val()
+				g.Assert(val).Eql(1)
+
+				balance, _ := c.Hash("TxCommit").Get("balance")
+				g.Assert(balance).Eql("7")
+			})
+
+			g.It("Executes writes of wrappers outside of transaction", func() {
+				c.String("TxOutsideA").Set("a")
+
+				abort := errors.New("abort")
+				err := c.Tx([]string{"TxOutsideA"}, func(tx *Tx) error {
+					tx.String("TxOutsideB").Set("b")
+
+					values, err := tx.MGet("TxOutsideA", "TxOutsideB")
+					g.Assert(values).Eql([]string{"a", "b"})
+					g.Assert(err).Eql(nil)
+
+					tx.Pipe().List("TxOutsideC").RPush("c")
+					return abort
+				})
+				g.Assert(err).Eql(abort)
+
+				values, _ := c.MGet("TxOutsideA", "TxOutsideB")
+				g.Assert(values).Eql([]string{"a", "b"})
+
+				length, _ := c.List("TxOutsideC").Len()
+				g.Assert(length).Eql(0)
+			})
+
+			g.It("Retries when watched key was modified", func() {
+				c.Raw.Do(radix.Cmd(nil, "HSET", "TxRetry", "n", "1"))
+
+				attempts := 0
+				err := c.Tx([]string{"TxRetry"}, func(tx *Tx) error {
+					attempts++
+					if attempts == 1 {
+						c.Hash("TxRetry").Incr("n", 10)
+					}
+					tx.Pipe().Hash("TxRetry").Incr("n", 1)
+					return nil
+				})
+				g.Assert(err).Eql(nil)
+				g.Assert(attempts).Eql(2)
+
+				n, _ := c.Hash("TxRetry").Get("n")
+				g.Assert(n).Eql("12")
+			})
+
+			g.It("Returns ErrTxConflict when retries are exhausted", func() {
+				attempts := 0
+				var incr *IntResult
+				err := c.Tx([]string{"TxConflict"}, func(tx *Tx) error {
+					attempts++
+					c.List("TxConflict").Push("x")
+					incr = tx.Pipe().Hash("TxConflictHash").Incr("n", 1)
+					return nil
+				})
+				g.Assert(err).Eql(ErrTxConflict)
+				g.Assert(attempts).Eql(TxMaxAttempts)
+				g.Assert(incr.Err()).Eql(ErrTxConflict)
+
+				exists, _ := c.Hash("TxConflictHash").Exists("n")
+				g.Assert(exists).IsFalse()
+			})
+
+			g.It("Aborts when fn returns error", func() {
+				abort := errors.New("abort")
+				err := c.Tx([]string{"TxAbort"}, func(tx *Tx) error {
+					tx.Pipe().List("TxAbort").Push("x")
+					return abort
+				})
+				g.Assert(err).Eql(abort)
+
+				length, _ := c.List("TxAbort").Len()
+				g.Assert(length).Eql(0)
+			})
+
+			g.It("Reports errors of queued commands", func() {
+				c.Raw.Do(radix.Cmd(nil, "HSET", "TxWrongType", "a", "1"))
+
+				var pop *StringResult
+				var push *IntResult
+				err := c.Tx(nil, func(tx *Tx) error {
+					pop = tx.Pipe().List("TxWrongType").Pop()
+					push = tx.Pipe().List("TxWrongTypeOther").Push("x")
+					return nil
+				})
+				g.Assert(err).Eql(ErrWrongType)
+				g.Assert(pop.Err()).Eql(ErrWrongType)
+
+				length, _ := push.Val()
+				g.Assert(length).Eql(1)
+			})
+
+			g.It("Encrypts queued writes", func() {
+				key, _ := NewAESGCM(make([]byte, 32))
+				enc, _ := NewEncryption("k", map[string]cipher.AEAD{"k": key})
+
+				var email *StringResult
+				err := c.WithTransformer(enc).Tx([]string{"TxEncrypt"}, func(tx *Tx) error {
+					tx.Pipe().Hash("TxEncrypt").Set("email", "bob@example.com")
+					tx.Pipe().List("TxEncryptLog").RPush("bob@example.com")
+					email = tx.Pipe().Hash("TxEncrypt").Get("email")
+					return nil
+				})
+				g.Assert(err).Eql(nil)
+
+				val, _ := email.Val()
+				g.Assert(val).Eql("bob@example.com")
+
+				raw, _ := c.Hash("TxEncrypt").Get("email")
+				g.Assert(strings.HasPrefix(raw, string(encryptMagic))).IsTrue()
+				g.Assert(strings.Contains(raw, "bob")).IsFalse()
+
+				raw, _ = c.List("TxEncryptLog").Index(0)
+				g.Assert(strings.HasPrefix(raw, string(encryptMagic))).IsTrue()
+			})
+		})
+	})
+}
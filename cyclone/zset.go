@@ -0,0 +1,501 @@
+package cyclone
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/mediocregopher/radix/v3"
+)
+
+// ZSet wraps redis sorted set operations.
+type ZSet struct {
+	Key
+}
+
+// ZMember is a member of sorted set with its score.
+type ZMember struct {
+	Member string
+	Score  float64
+}
+
+// ZAdd is a ZADD command builder.
+type ZAdd struct {
+	zset    *ZSet
+	members []ZMember
+	args    []string
+}
+
+// ZRange is a ZRANGE command builder.
+type ZRange struct {
+	zset        *ZSet
+	start, stop string
+	by          string
+	rev         bool
+	limit       []string
+}
+
+// ZSetScanIterator allows for channel based iteration.
+type ZSetScanIterator struct {
+	scanCtl
+	zset *ZSet
+	opts radix.ScanOpts
+}
+
+// Add adds all the specified members with the specified scores to the sorted set
+// stored at key. If a specified member is already a member of the sorted set,
+// the score is updated and the element reinserted at the right position
+// to ensure the correct ordering. Use AddWith for options.
+// https://redis.io/commands/zadd
+//
+// Time complexity: O(log(N)) for each item added, where N is the number
+//                  of elements in the sorted set.
+func (z *ZSet) Add(members ...ZMember) (addedMembers int, err error) {
+	return z.AddWith(members...).Do()
+}
+
+// AddWith returns ZADD command builder with NX/XX/GT/LT/CH/INCR options.
+// https://redis.io/commands/zadd
+//
+//   changed, err := c.ZSet("scores").AddWith(cyclone.ZMember{"bob", 10}).GT().CH().Do()
+func (z *ZSet) AddWith(members ...ZMember) *ZAdd {
+	return &ZAdd{zset: z, members: members}
+}
+
+// BPopMax (BZPOPMAX) is the blocking variant of PopMax. It pops the member with
+// the highest score from the first non-empty sorted set, with the given keys
+// being checked in the order that they are given. Key of the sorted set
+// the member was popped from is returned. A timeout of zero can be used
+// to block indefinitely. ErrNil is returned when timeout is reached.
+// https://redis.io/commands/bzpopmax
+//
+// Time complexity: O(log(N)) with N being the number of elements in the sorted set.
+func (z *ZSet) BPopMax(timeout time.Duration, others ...*ZSet) (key string, member ZMember, err error) {
+	return z.bpop("BZPOPMAX", timeout, others)
+}
+
+// BPopMin (BZPOPMIN) is the blocking variant of PopMin. It pops the member with
+// the lowest score from the first non-empty sorted set, with the given keys
+// being checked in the order that they are given. Key of the sorted set
+// the member was popped from is returned. A timeout of zero can be used
+// to block indefinitely. ErrNil is returned when timeout is reached.
+// https://redis.io/commands/bzpopmin
+//
+// Time complexity: O(log(N)) with N being the number of elements in the sorted set.
+func (z *ZSet) BPopMin(timeout time.Duration, others ...*ZSet) (key string, member ZMember, err error) {
+	return z.bpop("BZPOPMIN", timeout, others)
+}
+
+// Card returns the sorted set cardinality (number of elements) of the sorted set stored at key.
+// https://redis.io/commands/zcard
+//
+// Time complexity: O(1)
+func (z *ZSet) Card() (card int, err error) {
+	err = z.cyclone.do(radix.Cmd(&card, "ZCARD", z.key))
+	return
+}
+
+// Count returns the number of elements in the sorted set at key with a score
+// between min and max. Bounds are inclusive by default, prefix score with "("
+// for an exclusive bound, "-inf" and "+inf" can be used as well.
+// https://redis.io/commands/zcount
+//
+// Time complexity: O(log(N)) with N being the number of elements in the sorted set.
+func (z *ZSet) Count(min, max string) (count int, err error) {
+	err = z.cyclone.do(radix.Cmd(&count, "ZCOUNT", z.key, min, max))
+	return
+}
+
+// Incr increments the score of member in the sorted set stored at key by increment.
+// If member does not exist in the sorted set, it is added with increment as its
+// score (as if its previous score was 0.0).
+// https://redis.io/commands/zincrby
+//
+// Time complexity: O(log(N)) where N is the number of elements in the sorted set.
+func (z *ZSet) Incr(member string, by float64) (scoreAfterIncr float64, err error) {
+	err = z.cyclone.do(radix.Cmd(
+		&scoreAfterIncr,
+		"ZINCRBY",
+		z.key,
+		formatScore(by),
+		member,
+	))
+	return
+}
+
+// MScore returns the scores associated with the specified members in the sorted set
+// stored at key. For every member that does not exist in the sorted set,
+// nil is returned.
+// https://redis.io/commands/zmscore
+//
+// Time complexity: O(N) where N is the number of members being requested.
+func (z *ZSet) MScore(members ...string) (scores []*float64, err error) {
+	var reply []interface{}
+	args := append([]string{z.key}, members...)
+	if err = z.cyclone.do(radix.Cmd(&reply, "ZMSCORE", args...)); err != nil {
+		return
+	}
+	scores = make([]*float64, len(reply))
+	for i, v := range reply {
+		// nil replies are decoded as empty, score is never empty
+		b, ok := v.([]byte)
+		if !ok || len(b) == 0 {
+			continue
+		}
+		score, err := strconv.ParseFloat(string(b), 64)
+		if err != nil {
+			return nil, err
+		}
+		scores[i] = &score
+	}
+	return
+}
+
+// PopMax removes and returns up to count members with the highest scores
+// in the sorted set stored at key.
+// https://redis.io/commands/zpopmax
+//
+// Time complexity: O(log(N)*M) with N being the number of elements in the sorted set,
+//                  and M being the number of elements popped.
+func (z *ZSet) PopMax(count int) ([]ZMember, error) {
+	return z.pop("ZPOPMAX", count)
+}
+
+// PopMin removes and returns up to count members with the lowest scores
+// in the sorted set stored at key.
+// https://redis.io/commands/zpopmin
+//
+// Time complexity: O(log(N)*M) with N being the number of elements in the sorted set,
+//                  and M being the number of elements popped.
+func (z *ZSet) PopMin(count int) ([]ZMember, error) {
+	return z.pop("ZPOPMIN", count)
+}
+
+// Range returns ZRANGE query builder. By default it queries all members by index
+// ordered from the lowest to the highest score.
+// https://redis.io/commands/zrange
+//
+//   top, err := c.ZSet("scores").Range().Index(0, 9).Rev().WithScores()
+//
+// Time complexity: O(log(N)+M) with N being the number of elements in the sorted set
+//                  and M the number of elements returned.
+func (z *ZSet) Range() *ZRange {
+	return &ZRange{zset: z, start: "0", stop: "-1"}
+}
+
+// Rank returns the rank of member in the sorted set stored at key, with the scores
+// ordered from low to high. The rank is 0-based. ErrNil is returned when member
+// does not exist.
+// https://redis.io/commands/zrank
+//
+// Time complexity: O(log(N))
+func (z *ZSet) Rank(member string) (rank int, err error) {
+	mn := radix.MaybeNil{Rcv: &rank}
+	err = z.cyclone.do(radix.Cmd(&mn, "ZRANK", z.key, member))
+	err = nilErr(&mn, err)
+	return
+}
+
+// Rem removes the specified members from the sorted set stored at key.
+// Non existing members are ignored.
+// https://redis.io/commands/zrem
+//
+// Time complexity: O(M*log(N)) with N being the number of elements in the sorted set
+//                  and M the number of elements to be removed.
+func (z *ZSet) Rem(members ...interface{}) (removedMembers int, err error) {
+	err = z.cyclone.do(radix.FlatCmd(&removedMembers, "ZREM", z.key, members...))
+	return
+}
+
+// RevRank returns the rank of member in the sorted set stored at key, with the scores
+// ordered from high to low. The rank is 0-based. ErrNil is returned when member
+// does not exist.
+// https://redis.io/commands/zrevrank
+//
+// Time complexity: O(log(N))
+func (z *ZSet) RevRank(member string) (rank int, err error) {
+	mn := radix.MaybeNil{Rcv: &rank}
+	err = z.cyclone.do(radix.Cmd(&mn, "ZREVRANK", z.key, member))
+	err = nilErr(&mn, err)
+	return
+}
+
+// Scan iterates members of sorted set types and their associated scores.
+// https://redis.io/commands/zscan
+// https://redis.io/commands/scan
+//
+// Time complexity: O(1) for every call. O(N) for a complete iteration, including
+//                  enough command calls for the cursor to return back to 0.
+//                  N is the number of elements inside the collection.
+func (z *ZSet) Scan() *ZSetScanIterator {
+	return &ZSetScanIterator{zset: z}
+}
+
+// Score returns the score of member in the sorted set at key.
+// ErrNil is returned when member does not exist.
+// https://redis.io/commands/zscore
+//
+// Time complexity: O(1)
+func (z *ZSet) Score(member string) (score float64, err error) {
+	mn := radix.MaybeNil{Rcv: &score}
+	err = z.cyclone.do(radix.Cmd(&mn, "ZSCORE", z.key, member))
+	err = nilErr(&mn, err)
+	return
+}
+
+func (z *ZSet) pop(cmd string, count int) ([]ZMember, error) {
+	var reply []string
+	if err := z.cyclone.do(radix.Cmd(&reply, cmd, z.key, strconv.Itoa(count))); err != nil {
+		return nil, err
+	}
+	return parseZMembers(reply)
+}
+
+func (z *ZSet) bpop(cmd string, timeout time.Duration, others []*ZSet) (key string, member ZMember, err error) {
+	args := make([]string, 0, len(others)+2)
+	args = append(args, z.key)
+	for _, other := range others {
+		args = append(args, other.key)
+	}
+	args = append(args, formatTimeout(timeout))
+
+	var reply []string
+	mn := radix.MaybeNil{Rcv: &reply}
+	err = z.cyclone.doBlocking(radix.Cmd(&mn, cmd, args...))
+	err = nilErr(&mn, err)
+	if err != nil || len(reply) != 3 {
+		return
+	}
+
+	key = reply[0]
+	member.Member = reply[1]
+	member.Score, err = strconv.ParseFloat(reply[2], 64)
+	return
+}
+
+// NX only adds new elements, it does not update already existing elements.
+func (a *ZAdd) NX() *ZAdd {
+	a.args = append(a.args, "NX")
+	return a
+}
+
+// XX only updates elements that already exist, it does not add new elements.
+func (a *ZAdd) XX() *ZAdd {
+	a.args = append(a.args, "XX")
+	return a
+}
+
+// GT only updates existing elements if the new score is greater than the current score.
+func (a *ZAdd) GT() *ZAdd {
+	a.args = append(a.args, "GT")
+	return a
+}
+
+// LT only updates existing elements if the new score is less than the current score.
+func (a *ZAdd) LT() *ZAdd {
+	a.args = append(a.args, "LT")
+	return a
+}
+
+// CH modifies the return value of Do from the number of new elements added,
+// to the total number of elements changed.
+func (a *ZAdd) CH() *ZAdd {
+	a.args = append(a.args, "CH")
+	return a
+}
+
+// Do executes ZADD and returns the number of added (or changed with CH) elements.
+func (a *ZAdd) Do() (count int, err error) {
+	err = a.zset.cyclone.do(radix.Cmd(&count, "ZADD", a.cmdArgs()...))
+	return
+}
+
+// Incr executes ZADD with INCR option, which acts like Incr. Only one member can be
+// specified. ErrNil is returned when the operation was aborted by a condition.
+func (a *ZAdd) Incr() (scoreAfterIncr float64, err error) {
+	if len(a.members) != 1 {
+		return 0, errors.New("cyclone: ZADD INCR requires exactly one member")
+	}
+	a.args = append(a.args, "INCR")
+
+	mn := radix.MaybeNil{Rcv: &scoreAfterIncr}
+	err = a.zset.cyclone.do(radix.Cmd(&mn, "ZADD", a.cmdArgs()...))
+	err = nilErr(&mn, err)
+	return
+}
+
+func (a *ZAdd) cmdArgs() []string {
+	args := make([]string, 0, 1+len(a.args)+2*len(a.members))
+	args = append(args, a.zset.key)
+	args = append(args, a.args...)
+	for _, m := range a.members {
+		args = append(args, formatScore(m.Score), m.Member)
+	}
+	return args
+}
+
+// Index queries members by index range, both start and stop are inclusive and
+// zero-based. Negative indexes designate elements starting from the end.
+func (r *ZRange) Index(start, stop int) *ZRange {
+	r.start, r.stop, r.by = strconv.Itoa(start), strconv.Itoa(stop), ""
+	return r
+}
+
+// ByScore queries members with scores between min and max. Bounds are inclusive
+// by default, prefix score with "(" for an exclusive bound, "-inf" and "+inf"
+// can be used as well.
+func (r *ZRange) ByScore(min, max string) *ZRange {
+	r.start, r.stop, r.by = min, max, "BYSCORE"
+	return r
+}
+
+// ByLex queries members between min and max in lexicographical order, when all
+// members have the same score. Bounds must start with "[" (inclusive) or "("
+// (exclusive), "-" and "+" mean infinitely small and infinitely large strings.
+func (r *ZRange) ByLex(min, max string) *ZRange {
+	r.start, r.stop, r.by = min, max, "BYLEX"
+	return r
+}
+
+// Rev reverses the ordering, so members are ordered from the highest to the lowest score.
+// Bounds of ByScore and ByLex are still given as min and max.
+func (r *ZRange) Rev() *ZRange {
+	r.rev = true
+	return r
+}
+
+// Limit returns only count members starting at offset. Negative count returns
+// all members from offset. Limit is applicable only with ByScore and ByLex.
+func (r *ZRange) Limit(offset, count int) *ZRange {
+	r.limit = []string{"LIMIT", strconv.Itoa(offset), strconv.Itoa(count)}
+	return r
+}
+
+// Members executes ZRANGE and returns members without scores.
+func (r *ZRange) Members() (members []string, err error) {
+	err = r.zset.cyclone.do(radix.Cmd(&members, "ZRANGE", r.cmdArgs()...))
+	return
+}
+
+// WithScores executes ZRANGE and returns members with their scores.
+// It cannot be used with ByLex.
+func (r *ZRange) WithScores() ([]ZMember, error) {
+	var reply []string
+	args := append(r.cmdArgs(), "WITHSCORES")
+	if err := r.zset.cyclone.do(radix.Cmd(&reply, "ZRANGE", args...)); err != nil {
+		return nil, err
+	}
+	return parseZMembers(reply)
+}
+
+func (r *ZRange) cmdArgs() []string {
+	start, stop := r.start, r.stop
+	if r.rev && r.by != "" {
+		// reversed BYSCORE and BYLEX expect max before min
+		start, stop = stop, start
+	}
+
+	args := []string{r.zset.key, start, stop}
+	if r.by != "" {
+		args = append(args, r.by)
+	}
+	if r.rev {
+		args = append(args, "REV")
+	}
+	return append(args, r.limit...)
+}
+
+// Count sets count hint for iterator. Redis default hint is 10 when not specified.
+// https://redis.io/commands/scan#the-count-option
+//
+func (i *ZSetScanIterator) Count(count int) *ZSetScanIterator {
+	i.opts.Count = count
+	return i
+}
+
+// Match sets match pattern for iterator.
+// https://redis.io/commands/scan#the-match-option
+//
+func (i *ZSetScanIterator) Match(pattern string) *ZSetScanIterator {
+	i.opts.Pattern = pattern
+	return i
+}
+
+// From sets cursor to start iteration from, e.g. one returned by Cursor
+// of an interrupted iteration. Elements may be returned more than once.
+func (i *ZSetScanIterator) From(cursor string) *ZSetScanIterator {
+	i.setCursor(cursor)
+	return i
+}
+
+// Chan returns channel and starts iteration.
+// It will send Members/Scores separately. Iteration stops and the channel
+// is closed when Cyclone's context is done or Stop is called, check Err after
+// the channel is closed.
+func (i *ZSetScanIterator) Chan(bufferSize int) <-chan string {
+	ch := make(chan string, bufferSize)
+	pager := i.pager()
+
+	go func() {
+		defer close(ch)
+		scanChan(pager, &i.scanCtl, sendStrings(ch))
+	}()
+	return ch
+}
+
+// ChanMembers returns channel and starts iteration.
+// It will send ZMember struct containing Member and Score. Iteration stops
+// and the channel is closed when Cyclone's context is done or Stop is called,
+// check Err after the channel is closed.
+func (i *ZSetScanIterator) ChanMembers(bufferSize int) <-chan ZMember {
+	ch := make(chan ZMember, bufferSize)
+	pager := i.pager()
+
+	go func() {
+		defer close(ch)
+		scanChan(pager, &i.scanCtl, func(elems []string, abort, done <-chan struct{}) bool {
+			members, err := parseZMembers(elems)
+			if err != nil {
+				i.fail(err)
+				return false
+			}
+			for _, m := range members {
+				select {
+				case ch <- m:
+				case <-abort:
+					return false
+				case <-done:
+					return false
+				}
+			}
+			return true
+		})
+	}()
+	return ch
+}
+
+// pager returns ZSCAN pager with iterator options.
+func (i *ZSetScanIterator) pager() scanPager {
+	i.opts.Command = "ZSCAN"
+	i.opts.Key = i.zset.key
+	return scanPager{cyclone: i.zset.cyclone, opts: i.opts, cursor: i.Cursor()}
+}
+
+// parseZMembers parses flat member/score reply.
+func parseZMembers(reply []string) ([]ZMember, error) {
+	members := make([]ZMember, len(reply)/2)
+	for i := range members {
+		members[i].Member = reply[2*i]
+		score, err := strconv.ParseFloat(reply[2*i+1], 64)
+		if err != nil {
+			return nil, err
+		}
+		members[i].Score = score
+	}
+	return members, nil
+}
+
+// formatScore formats score as expected by sorted set commands.
+func formatScore(score float64) string {
+	return strconv.FormatFloat(score, 'f', -1, 64)
+}
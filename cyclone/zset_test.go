@@ -0,0 +1,293 @@
+package cyclone
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/franela/goblin"
+)
+
+func TestZSet(t *testing.T) {
+	g := goblin.Goblin(t)
+
+	withConn(func(c *Cyclone) {
+		g.Describe(".Add", func() {
+			g.It("Adds members and returns added count", func() {
+				z := c.ZSet("ZSetAdd")
+
+				added, err := z.Add(ZMember{"a", 1}, ZMember{"b", 2})
+				g.Assert(added).Eql(2)
+				g.Assert(err).Eql(nil)
+
+				added, _ = z.Add(ZMember{"a", 3}, ZMember{"c", 4})
+				g.Assert(added).Eql(1)
+
+				score, _ := z.Score("a")
+				g.Assert(score).Eql(3.0)
+			})
+
+			g.It("Returns ErrWrongType for non-zset keys", func() {
+				c.Set("ZSetAddSet").Add("a")
+
+				_, err := c.ZSet("ZSetAddSet").Add(ZMember{"a", 1})
+				g.Assert(err).Eql(ErrWrongType)
+			})
+		})
+
+		g.Describe(".AddWith", func() {
+			g.It("Respects NX and XX", func() {
+				z := c.ZSet("ZSetAddWithNX")
+				z.Add(ZMember{"a", 1})
+
+				added, _ := z.AddWith(ZMember{"a", 5}, ZMember{"b", 2}).NX().Do()
+				g.Assert(added).Eql(1)
+				score, _ := z.Score("a")
+				g.Assert(score).Eql(1.0)
+
+				changed, _ := z.AddWith(ZMember{"a", 5}, ZMember{"c", 2}).XX().CH().Do()
+				g.Assert(changed).Eql(1)
+				_, err := z.Score("c")
+				g.Assert(err).Eql(ErrNil)
+			})
+
+			g.It("Respects GT and LT", func() {
+				z := c.ZSet("ZSetAddWithGT")
+				z.Add(ZMember{"a", 5})
+
+				changed, _ := z.AddWith(ZMember{"a", 3}).GT().CH().Do()
+				g.Assert(changed).Eql(0)
+
+				changed, _ = z.AddWith(ZMember{"a", 3}).LT().CH().Do()
+				g.Assert(changed).Eql(1)
+			})
+
+			g.It("Increments with INCR", func() {
+				z := c.ZSet("ZSetAddWithIncr")
+
+				score, err := z.AddWith(ZMember{"a", 1.5}).Incr()
+				g.Assert(score).Eql(1.5)
+				g.Assert(err).Eql(nil)
+
+				_, err = z.AddWith(ZMember{"a", 1}).NX().Incr()
+				g.Assert(err).Eql(ErrNil)
+
+				_, err = z.AddWith(ZMember{"a", 1}, ZMember{"b", 1}).Incr()
+				g.Assert(err == nil).IsFalse()
+			})
+		})
+
+		g.Describe(".BPopMin", func() {
+			g.It("Pops member from the first non-empty sorted set", func() {
+				first := c.ZSet("ZSetBPopMinFirst")
+				second := c.ZSet("ZSetBPopMinSecond")
+				second.Add(ZMember{"a", 1}, ZMember{"b", 2})
+
+				key, member, err := first.BPopMin(time.Second, second)
+				g.Assert(err).Eql(nil)
+				g.Assert(key).Eql("ZSetBPopMinSecond")
+				g.Assert(member).Eql(ZMember{"a", 1})
+
+				key, member, _ = second.BPopMax(time.Second)
+				g.Assert(member).Eql(ZMember{"b", 2})
+			})
+
+			g.It("Returns ErrNil on timeout", func() {
+				_, _, err := c.ZSet("ZSetBPopMinTimeout").BPopMin(100 * time.Millisecond)
+				g.Assert(err).Eql(ErrNil)
+			})
+
+			g.It("Is interrupted by context", func() {
+				ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+				defer cancel()
+
+				_, _, err := c.WithContext(ctx).ZSet("ZSetBPopMinCtx").BPopMin(0)
+				g.Assert(err).Eql(context.DeadlineExceeded)
+			})
+		})
+
+		g.Describe(".Card", func() {
+			g.It("Returns number of members", func() {
+				c.ZSet("ZSetCard").Add(ZMember{"a", 1}, ZMember{"b", 2})
+
+				card, _ := c.ZSet("ZSetCard").Card()
+				g.Assert(card).Eql(2)
+			})
+		})
+
+		g.Describe(".Count", func() {
+			g.It("Counts members within score range", func() {
+				z := c.ZSet("ZSetCount")
+				z.Add(ZMember{"a", 1}, ZMember{"b", 2}, ZMember{"c", 3})
+
+				count, _ := z.Count("-inf", "+inf")
+				g.Assert(count).Eql(3)
+				count, _ = z.Count("(1", "3")
+				g.Assert(count).Eql(2)
+			})
+		})
+
+		g.Describe(".Incr", func() {
+			g.It("Increments score", func() {
+				z := c.ZSet("ZSetIncr")
+
+				score, _ := z.Incr("a", 2.5)
+				g.Assert(score).Eql(2.5)
+				score, _ = z.Incr("a", -1)
+				g.Assert(score).Eql(1.5)
+			})
+		})
+
+		g.Describe(".MScore", func() {
+			g.It("Returns scores with nil for missing members", func() {
+				z := c.ZSet("ZSetMScore")
+				z.Add(ZMember{"a", 1}, ZMember{"b", 2.5})
+
+				scores, err := z.MScore("a", "x", "b")
+				g.Assert(err).Eql(nil)
+				g.Assert(len(scores)).Eql(3)
+				g.Assert(*scores[0]).Eql(1.0)
+				g.Assert(scores[1] == nil).IsTrue()
+				g.Assert(*scores[2]).Eql(2.5)
+			})
+		})
+
+		g.Describe(".PopMin", func() {
+			g.It("Pops members with the lowest and highest scores", func() {
+				z := c.ZSet("ZSetPopMin")
+				z.Add(ZMember{"a", 1}, ZMember{"b", 2}, ZMember{"c", 3}, ZMember{"d", 4})
+
+				members, _ := z.PopMin(2)
+				g.Assert(members).Eql([]ZMember{{"a", 1}, {"b", 2}})
+
+				members, _ = z.PopMax(1)
+				g.Assert(members).Eql([]ZMember{{"d", 4}})
+			})
+		})
+
+		g.Describe(".Range", func() {
+			z := c.ZSet("ZSetRange")
+
+			g.BeforeEach(func() {
+				z.Add(ZMember{"a", 1}, ZMember{"b", 2}, ZMember{"c", 3}, ZMember{"d", 4})
+			})
+
+			g.It("Returns all members by default", func() {
+				members, _ := z.Range().Members()
+				g.Assert(members).Eql([]string{"a", "b", "c", "d"})
+			})
+
+			g.It("Returns members by index with scores", func() {
+				members, _ := z.Range().Index(0, 1).Rev().WithScores()
+				g.Assert(members).Eql([]ZMember{{"d", 4}, {"c", 3}})
+			})
+
+			g.It("Returns members by score", func() {
+				members, _ := z.Range().ByScore("(1", "+inf").Limit(1, 2).Members()
+				g.Assert(members).Eql([]string{"c", "d"})
+
+				members, _ = z.Range().ByScore("2", "3").Rev().Members()
+				g.Assert(members).Eql([]string{"c", "b"})
+			})
+
+			g.It("Returns members lexicographically", func() {
+				lex := c.ZSet("ZSetRangeLex")
+				lex.Add(ZMember{"a", 0}, ZMember{"b", 0}, ZMember{"c", 0})
+
+				members, _ := lex.Range().ByLex("[b", "+").Members()
+				g.Assert(members).Eql([]string{"b", "c"})
+			})
+		})
+
+		g.Describe(".Rank", func() {
+			g.It("Returns rank or ErrNil", func() {
+				z := c.ZSet("ZSetRank")
+				z.Add(ZMember{"a", 1}, ZMember{"b", 2}, ZMember{"c", 3})
+
+				rank, _ := z.Rank("b")
+				g.Assert(rank).Eql(1)
+				rank, _ = z.RevRank("a")
+				g.Assert(rank).Eql(2)
+
+				_, err := z.Rank("x")
+				g.Assert(err).Eql(ErrNil)
+				_, err = z.RevRank("x")
+				g.Assert(err).Eql(ErrNil)
+			})
+		})
+
+		g.Describe(".Rem", func() {
+			g.It("Removes members", func() {
+				z := c.ZSet("ZSetRem")
+				z.Add(ZMember{"a", 1}, ZMember{"b", 2})
+
+				removed, _ := z.Rem("a", "x")
+				g.Assert(removed).Eql(1)
+
+				card, _ := z.Card()
+				g.Assert(card).Eql(1)
+			})
+		})
+
+		g.Describe(".Score", func() {
+			g.It("Returns ErrNil for missing member", func() {
+				_, err := c.ZSet("ZSetScore").Score("x")
+				g.Assert(err).Eql(ErrNil)
+			})
+		})
+
+		g.Describe(".Scan", func() {
+			g.It("Iterates members and scores", func() {
+				z := c.ZSet("ZSetScan")
+				z.Add(ZMember{"a", 1}, ZMember{"b", 2.5})
+
+				elems := []string{}
+				for elem := range z.Scan().Chan(10) {
+					elems = append(elems, elem)
+				}
+				g.Assert(len(elems)).Eql(4)
+
+				scores := map[string]float64{}
+				for m := range z.Scan().Count(1).ChanMembers(10) {
+					scores[m.Member] = m.Score
+				}
+				g.Assert(scores).Eql(map[string]float64{"a": 1, "b": 2.5})
+			})
+
+			g.It("Filters members by pattern", func() {
+				z := c.ZSet("ZSetScanMatch")
+				z.Add(ZMember{"ab", 1}, ZMember{"ba", 2})
+
+				members := []ZMember{}
+				for m := range z.Scan().Match("a*").ChanMembers(10) {
+					members = append(members, m)
+				}
+				g.Assert(members).Eql([]ZMember{{"ab", 1}})
+			})
+
+			g.It("Reports error which ended iteration", func() {
+				c.String("ZSetScanWrongType").Set("a")
+
+				it := c.ZSet("ZSetScanWrongType").Scan()
+				for range it.ChanMembers(0) {
+				}
+				g.Assert(it.Err()).Eql(ErrWrongType)
+			})
+		})
+	})
+
+	g.Describe(".Scan scores", func() {
+		g.It("Reports invalid score", func() {
+			c := scanStub("ZSCAN", []string{"a", "1"}, []string{"b", "x"})
+
+			members := []ZMember{}
+			it := c.ZSet("ZSetScanScore").Scan()
+			for m := range it.ChanMembers(0) {
+				members = append(members, m)
+			}
+			g.Assert(members).Eql([]ZMember{{"a", 1}})
+			g.Assert(it.Err() == nil).IsFalse()
+			g.Assert(it.Cursor()).Eql("1")
+		})
+	})
+}